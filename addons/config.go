@@ -18,16 +18,6 @@ type UpdateAddonConfigOpts struct {
 }
 
 func UpdateConfig(ctx context.Context, app, addon string, options UpdateAddonConfigOpts) error {
-	weekdayNameToWeekdayOrderMap := map[string]time.Weekday{
-		strings.ToLower(time.Sunday.String()):    time.Sunday,
-		strings.ToLower(time.Monday.String()):    time.Monday,
-		strings.ToLower(time.Tuesday.String()):   time.Tuesday,
-		strings.ToLower(time.Wednesday.String()): time.Wednesday,
-		strings.ToLower(time.Thursday.String()):  time.Thursday,
-		strings.ToLower(time.Friday.String()):    time.Friday,
-		strings.ToLower(time.Saturday.String()):  time.Saturday,
-	}
-
 	c, err := config.ScalingoClient(ctx)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "get Scalingo client to update addon config")
@@ -56,11 +46,10 @@ func UpdateConfig(ctx context.Context, app, addon string, options UpdateAddonCon
 	)
 
 	if options.MaintenanceWindowDay != nil {
-		day, ok := weekdayNameToWeekdayOrderMap[strings.ToLower(*options.MaintenanceWindowDay)]
-		if !ok {
-			return errors.Wrapf(ctx, err, "invalid weekday '%s'", *options.MaintenanceWindowDay)
+		weekdayLocal, err = utils.ParseWeekday(ctx, *options.MaintenanceWindowDay)
+		if err != nil {
+			return err
 		}
-		weekdayLocal = day
 	}
 
 	if options.MaintenanceWindowHour != nil {
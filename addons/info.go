@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"slices"
 	"strings"
 	"time"
 
@@ -32,12 +31,8 @@ func Info(ctx context.Context, app, addon string) error {
 		return errors.Wrap(ctx, err, "get addon information")
 	}
 
-	isDatabase := slices.ContainsFunc(db.SupportedDatabases, func(s string) bool {
-		return strings.EqualFold(s, addonInfo.AddonProvider.ID)
-	})
-
 	dbInfo := [][]string{}
-	if isDatabase {
+	if db.IsDatabaseAddon(&addonInfo) {
 		dbInfo, err = getDatabaseInfo(ctx, c, app, addon)
 		if err != nil {
 			return errors.Wrap(ctx, err, "get database information")
@@ -0,0 +1,471 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	cloneStatusCopied  = "copied"
+	cloneStatusSkipped = "skipped"
+	cloneStatusFailed  = "failed"
+)
+
+// variableReferenceRegexp matches the references to other variables in the
+// value of a variable, e.g. $SCALINGO_POSTGRESQL_URL or ${PORT}
+var variableReferenceRegexp = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?`)
+
+// addonVariablePrefixes are the prefixes of the environment variables
+// generated by the addons whose prefix is not derived from the addon
+// provider ID
+var addonVariablePrefixes = map[string]string{
+	"mongodb":  "SCALINGO_MONGO_",
+	"influxdb": "SCALINGO_INFLUX_",
+}
+
+type CloneOpts struct {
+	From       string
+	To         string
+	WithEnv    bool
+	WithAddons bool
+	// DomainsSuffix is appended to the first label of the domains of the
+	// source app, e.g. www.example.com with -staging becomes
+	// www-staging.example.com. The domains are not copied if empty.
+	DomainsSuffix string
+}
+
+// cloneReport lists what has been copied to the new app and what has not
+type cloneReport struct {
+	rows     [][]string
+	failures int
+}
+
+func (r *cloneReport) add(resource, status, details string) {
+	r.rows = append(r.rows, []string{resource, status, details})
+	if status == cloneStatusFailed {
+		r.failures++
+	}
+}
+
+func (r *cloneReport) addError(resource string, err error) {
+	r.add(resource, cloneStatusFailed, err.Error())
+}
+
+// Clone creates a new app with the stack, project and region of an existing
+// app, then copies its configuration: routing settings, formation,
+// autoscalers, notifiers, alerts and collaborators, and optionally its
+// environment, addons and domains. The steps failing don't stop the clone,
+// they are reported at the end.
+func Clone(ctx context.Context, opts CloneOpts) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "fail to get Scalingo client")
+	}
+
+	source, err := c.AppsShow(ctx, opts.From)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "fail to get the application %s in the region %s", opts.From, config.C.ScalingoRegion)
+	}
+	// The app is created in the region of the client, which must be the one
+	// of the source app
+	if source.Region != "" && config.C.ScalingoRegion != "" && source.Region != config.C.ScalingoRegion {
+		return errors.Newf(ctx, "the application %s is in the region %s, not in %s, use --region %s", source.Name, source.Region, config.C.ScalingoRegion, source.Region)
+	}
+	app, err := c.AppsCreate(ctx, scalingo.AppsCreateOpts{
+		Name:        opts.To,
+		StackID:     source.StackID,
+		ProjectID:   source.Project.ID,
+		HDSResource: source.HDSResource,
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "fail to create the application %s", opts.To)
+	}
+	io.Statusf("App '%s' has been created from '%s' in the region %s\n", app.Name, source.Name, config.C.ScalingoRegion)
+
+	report := &cloneReport{}
+	cloneRoutingSettings(ctx, c, source, app.Name, report)
+	if opts.WithAddons {
+		cloneAddons(ctx, c, source.Name, app.Name, report)
+	} else {
+		report.add("addons", cloneStatusSkipped, "use --with-addons to provision the same addons")
+	}
+	if opts.WithEnv {
+		cloneEnvironment(ctx, c, source.Name, app.Name, report)
+	} else {
+		report.add("environment", cloneStatusSkipped, "use --with-env to copy the environment variables")
+	}
+	cloneFormation(ctx, c, source.Name, app.Name, report)
+	cloneAutoscalers(ctx, c, source.Name, app.Name, report)
+	notifierIDs := cloneNotifiers(ctx, c, source.Name, app.Name, report)
+	cloneAlerts(ctx, c, source.Name, app.Name, notifierIDs, report)
+	cloneCollaborators(ctx, c, source.Name, app.Name, report)
+	if opts.DomainsSuffix != "" {
+		cloneDomains(ctx, c, source.Name, app.Name, opts.DomainsSuffix, report)
+	} else {
+		report.add("domains", cloneStatusSkipped, "use --with-domains-suffix to copy the domains")
+	}
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Resource", "Status", "Details"})
+	for _, row := range report.rows {
+		_ = t.Append(row)
+	}
+	_ = t.Render()
+
+	if report.failures > 0 {
+		return errors.Newf(ctx, "%d resources of %s failed to be copied to %s", report.failures, source.Name, app.Name)
+	}
+	return nil
+}
+
+func cloneRoutingSettings(ctx context.Context, c *scalingo.Client, source *scalingo.App, app string, report *cloneReport) {
+	settings := []struct {
+		name    string
+		enabled bool
+		update  func(context.Context, string, bool) (*scalingo.App, error)
+	}{
+		{"force HTTPS", source.ForceHTTPS, c.AppsForceHTTPS},
+		{"sticky session", source.StickySession, c.AppsStickySession},
+		{"router logs", source.RouterLogs, c.AppsRouterLogs},
+	}
+	for _, setting := range settings {
+		if !setting.enabled {
+			continue
+		}
+		_, err := setting.update(ctx, app, true)
+		if err != nil {
+			report.addError("routing setting "+setting.name, err)
+			continue
+		}
+		report.add("routing setting "+setting.name, cloneStatusCopied, "enabled")
+	}
+}
+
+func cloneAddons(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) {
+	addons, err := c.AddonsList(ctx, source)
+	if err != nil {
+		report.addError("addons", err)
+		return
+	}
+	for _, addon := range addons {
+		resource := "addon " + addon.AddonProvider.ID
+		_, err := c.AddonProvision(ctx, app, scalingo.AddonProvisionParams{
+			AddonProviderID: addon.AddonProvider.ID,
+			PlanID:          addon.Plan.ID,
+		})
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		report.add(resource, cloneStatusCopied, "plan "+addon.Plan.Name+", without its data")
+	}
+}
+
+// cloneEnvironment copies the environment variables, except the ones
+// generated by the addons of the source app or already set on the new app by
+// its addons. The aliases are copied as such, unless they reference a
+// variable generated by an addon which is not set on the new app.
+func cloneEnvironment(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) {
+	variables, err := c.VariablesListWithoutAlias(ctx, source)
+	if err != nil {
+		report.addError("environment", err)
+		return
+	}
+	addons, err := c.AddonsList(ctx, source)
+	if err != nil {
+		report.addError("environment", err)
+		return
+	}
+	existing, err := c.VariablesListWithoutAlias(ctx, app)
+	if err != nil {
+		report.addError("environment", err)
+		return
+	}
+
+	providerIDs := make([]string, 0, len(addons))
+	for _, addon := range addons {
+		providerIDs = append(providerIDs, addon.AddonProvider.ID)
+	}
+	toCopy, generated := filterAddonVariables(variables, providerIDs)
+	missing := slices.DeleteFunc(slices.Clone(generated), func(name string) bool {
+		_, ok := existing.Contains(name)
+		return ok
+	})
+	toCopy, dangling := filterDanglingAliases(toCopy, missing)
+	toCopy = slices.DeleteFunc(toCopy, func(variable *scalingo.Variable) bool {
+		if _, ok := existing.Contains(variable.Name); ok {
+			generated = append(generated, variable.Name)
+			return true
+		}
+		return false
+	})
+	if len(toCopy) > 0 {
+		_, err = c.VariableMultipleSet(ctx, app, toCopy)
+		if err != nil {
+			report.addError("environment", err)
+			return
+		}
+	}
+	report.add("environment", cloneStatusCopied, fmt.Sprintf("%d variables", len(toCopy)))
+	if len(generated) > 0 {
+		report.add("environment", cloneStatusSkipped, "generated by addons: "+strings.Join(generated, ", "))
+	}
+	if len(dangling) > 0 {
+		report.add("environment", cloneStatusSkipped, "aliases to variables not set on the new app: "+strings.Join(dangling, ", "))
+	}
+}
+
+// filterAddonVariables returns the variables to copy and the names of the
+// variables generated by the addons
+func filterAddonVariables(variables scalingo.Variables, providerIDs []string) (scalingo.Variables, []string) {
+	prefixes := make([]string, 0, len(providerIDs))
+	for _, providerID := range providerIDs {
+		prefix, ok := addonVariablePrefixes[providerID]
+		if !ok {
+			prefix = "SCALINGO_" + strings.ToUpper(strings.ReplaceAll(providerID, "-", "_")) + "_"
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	var toCopy scalingo.Variables
+	var generated []string
+	for _, variable := range variables {
+		isGenerated := slices.ContainsFunc(prefixes, func(prefix string) bool {
+			return strings.HasPrefix(variable.Name, prefix)
+		})
+		if isGenerated {
+			generated = append(generated, variable.Name)
+			continue
+		}
+		toCopy = append(toCopy, &scalingo.Variable{Name: variable.Name, Value: variable.Value})
+	}
+	return toCopy, generated
+}
+
+// filterDanglingAliases returns the variables not referencing any of the
+// missing variables and the names of the ones referencing them
+func filterDanglingAliases(variables scalingo.Variables, missing []string) (scalingo.Variables, []string) {
+	var kept scalingo.Variables
+	var dangling []string
+	for _, variable := range variables {
+		isDangling := slices.ContainsFunc(variableReferenceRegexp.FindAllStringSubmatch(variable.Value, -1), func(match []string) bool {
+			return slices.Contains(missing, match[1])
+		})
+		if isDangling {
+			dangling = append(dangling, variable.Name)
+			continue
+		}
+		kept = append(kept, variable)
+	}
+	return kept, dangling
+}
+
+// cloneFormation scales the new app like the source app. An app can only be
+// scaled once deployed, the command to run after the first deployment is
+// reported otherwise.
+func cloneFormation(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) {
+	formation, err := c.AppsContainerTypes(ctx, source)
+	if err != nil {
+		report.addError("formation", err)
+		return
+	}
+	formation = slices.DeleteFunc(formation, func(containerType scalingo.ContainerType) bool {
+		return containerType.Amount == 0
+	})
+	if len(formation) == 0 {
+		return
+	}
+
+	_, _, err = c.AppsScale(ctx, app, &scalingo.AppsScaleParams{Containers: formation})
+	if err != nil {
+		types := make([]string, 0, len(formation))
+		for _, containerType := range formation {
+			types = append(types, fmt.Sprintf("%s:%d:%s", containerType.Name, containerType.Amount, containerType.Size))
+		}
+		report.add("formation", cloneStatusSkipped, fmt.Sprintf("%v, run after the first deployment: scalingo --app %s scale %s", err, app, strings.Join(types, " ")))
+		return
+	}
+	report.add("formation", cloneStatusCopied, formatFormation(formation))
+}
+
+func cloneAutoscalers(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) {
+	autoscalers, err := c.AutoscalersList(ctx, source)
+	if err != nil {
+		report.addError("autoscalers", err)
+		return
+	}
+	for _, autoscaler := range autoscalers {
+		resource := "autoscaler " + autoscaler.ContainerType
+		created, err := c.AutoscalerAdd(ctx, app, scalingo.AutoscalerAddParams{
+			ContainerType: autoscaler.ContainerType,
+			Metric:        autoscaler.Metric,
+			Target:        autoscaler.Target,
+			MinContainers: autoscaler.MinContainers,
+			MaxContainers: autoscaler.MaxContainers,
+		})
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		if autoscaler.Disabled {
+			_, err = c.AutoscalerUpdate(ctx, app, created.ID, scalingo.AutoscalerUpdateParams{Disabled: &autoscaler.Disabled})
+			if err != nil {
+				report.addError(resource, err)
+				continue
+			}
+		}
+		report.add(resource, cloneStatusCopied, fmt.Sprintf("%s %.2f, %d to %d containers", autoscaler.Metric, autoscaler.Target, autoscaler.MinContainers, autoscaler.MaxContainers))
+	}
+}
+
+// cloneNotifiers copies the notifiers and returns the IDs of the new
+// notifiers by ID of the source notifiers
+func cloneNotifiers(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) map[string]string {
+	notifierIDs := map[string]string{}
+	notifiers, err := c.NotifiersList(ctx, source)
+	if err != nil {
+		report.addError("notifiers", err)
+		return notifierIDs
+	}
+	for _, detailedNotifier := range notifiers {
+		notifier := detailedNotifier.GetNotifier()
+		resource := "notifier " + notifier.Name
+
+		var typeData struct {
+			WebhookURL  string   `json:"webhook_url"`
+			Emails      []string `json:"emails"`
+			UserIDs     []string `json:"user_ids"`
+			PhoneNumber string   `json:"phone_number"`
+		}
+		if len(notifier.RawTypeData) > 0 {
+			err := json.Unmarshal(notifier.RawTypeData, &typeData)
+			if err != nil {
+				report.addError(resource, err)
+				continue
+			}
+		}
+		created, err := c.NotifierProvision(ctx, app, scalingo.NotifierParams{
+			Active:           notifier.Active,
+			Name:             notifier.Name,
+			SendAllEvents:    notifier.SendAllEvents,
+			SendAllAlerts:    notifier.SendAllAlerts,
+			SelectedEventIDs: notifier.SelectedEventIDs,
+			PlatformID:       notifier.PlatformID,
+			PhoneNumber:      typeData.PhoneNumber,
+			Emails:           typeData.Emails,
+			UserIDs:          typeData.UserIDs,
+			WebhookURL:       typeData.WebhookURL,
+		})
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		notifierIDs[notifier.ID] = created.ID
+		report.add(resource, cloneStatusCopied, string(notifier.Type))
+	}
+	return notifierIDs
+}
+
+// cloneAlerts copies the alerts, notifying the copies of their notifiers
+func cloneAlerts(ctx context.Context, c *scalingo.Client, source, app string, notifierIDs map[string]string, report *cloneReport) {
+	alerts, err := c.AlertsList(ctx, source)
+	if err != nil {
+		report.addError("alerts", err)
+		return
+	}
+	for _, alert := range alerts {
+		resource := fmt.Sprintf("alert %s %s", alert.ContainerType, alert.Metric)
+		var notifiers []string
+		for _, id := range alert.Notifiers {
+			if newID, ok := notifierIDs[id]; ok {
+				notifiers = append(notifiers, newID)
+			}
+		}
+		params := scalingo.AlertAddParams{
+			ContainerType:         alert.ContainerType,
+			Metric:                alert.Metric,
+			Limit:                 alert.Limit,
+			Disabled:              alert.Disabled,
+			DurationBeforeTrigger: &alert.DurationBeforeTrigger,
+			SendWhenBelow:         alert.SendWhenBelow,
+			Notifiers:             notifiers,
+		}
+		if remindEvery, err := time.ParseDuration(alert.RemindEvery); err == nil {
+			params.RemindEvery = &remindEvery
+		}
+		_, err := c.AlertAdd(ctx, app, params)
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		report.add(resource, cloneStatusCopied, fmt.Sprintf("limit %v", alert.Limit))
+	}
+}
+
+// cloneCollaborators invites the collaborators of the source app, except
+// the current user who owns the new app
+func cloneCollaborators(ctx context.Context, c *scalingo.Client, source, app string, report *cloneReport) {
+	collaborators, err := c.CollaboratorsList(ctx, source)
+	if err != nil {
+		report.addError("collaborators", err)
+		return
+	}
+	currentUserEmail := ""
+	currentUser, err := config.C.CurrentUser(ctx)
+	if err == nil && currentUser != nil {
+		currentUserEmail = currentUser.Email
+	}
+	for _, collaborator := range collaborators {
+		resource := "collaborator " + collaborator.Email
+		if collaborator.Email == currentUserEmail {
+			report.add(resource, cloneStatusSkipped, "owner of the new app")
+			continue
+		}
+		_, err := c.CollaboratorAdd(ctx, app, scalingo.CollaboratorAddParams{Email: collaborator.Email, IsLimited: collaborator.IsLimited})
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		report.add(resource, cloneStatusCopied, "invited")
+	}
+}
+
+func cloneDomains(ctx context.Context, c *scalingo.Client, source, app, suffix string, report *cloneReport) {
+	domains, err := c.DomainsList(ctx, source)
+	if err != nil {
+		report.addError("domains", err)
+		return
+	}
+	for _, domain := range domains {
+		name := suffixDomain(domain.Name, suffix)
+		resource := "domain " + domain.Name
+		_, err := c.DomainsAdd(ctx, app, scalingo.DomainsAddParams{Name: name})
+		if err != nil {
+			report.addError(resource, err)
+			continue
+		}
+		report.add(resource, cloneStatusCopied, "as "+name+", without its certificate")
+	}
+}
+
+// suffixDomain appends the suffix to the first label of the domain
+func suffixDomain(domain, suffix string) string {
+	label, rest, found := strings.Cut(domain, ".")
+	if !found {
+		return domain + suffix
+	}
+	return label + suffix + "." + rest
+}
@@ -0,0 +1,67 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestFilterAddonVariables(t *testing.T) {
+	variables := scalingo.Variables{
+		{ID: "1", Name: "SCALINGO_POSTGRESQL_URL", Value: "postgres://"},
+		{ID: "2", Name: "SCALINGO_MONGO_URL", Value: "mongodb://"},
+		{ID: "3", Name: "DATABASE_URL", Value: "$SCALINGO_POSTGRESQL_URL"},
+		{ID: "4", Name: "SCALINGO_REDIS_URL", Value: "redis://"},
+	}
+
+	toCopy, generated := filterAddonVariables(variables, []string{"postgresql", "mongodb"})
+
+	assert.Equal(t, scalingo.Variables{
+		{Name: "DATABASE_URL", Value: "$SCALINGO_POSTGRESQL_URL"},
+		{Name: "SCALINGO_REDIS_URL", Value: "redis://"},
+	}, toCopy)
+	assert.Equal(t, []string{"SCALINGO_POSTGRESQL_URL", "SCALINGO_MONGO_URL"}, generated)
+}
+
+func TestFilterDanglingAliases(t *testing.T) {
+	variables := scalingo.Variables{
+		{Name: "DATABASE_URL", Value: "$SCALINGO_POSTGRESQL_URL"},
+		{Name: "CACHE_URL", Value: "${SCALINGO_REDIS_URL}/1"},
+		{Name: "REDIS_URL", Value: "$SCALINGO_REDIS_URL"},
+		{Name: "LISTEN", Value: "0.0.0.0:$PORT"},
+		{Name: "SECRET", Value: "secret"},
+	}
+
+	kept, dangling := filterDanglingAliases(variables, []string{"SCALINGO_REDIS_URL"})
+
+	assert.Equal(t, scalingo.Variables{
+		{Name: "DATABASE_URL", Value: "$SCALINGO_POSTGRESQL_URL"},
+		{Name: "LISTEN", Value: "0.0.0.0:$PORT"},
+		{Name: "SECRET", Value: "secret"},
+	}, kept)
+	assert.Equal(t, []string{"CACHE_URL", "REDIS_URL"}, dangling)
+}
+
+func TestSuffixDomain(t *testing.T) {
+	tests := map[string]struct {
+		domain         string
+		expectedDomain string
+	}{
+		"subdomain": {
+			domain:         "www.example.com",
+			expectedDomain: "www-staging.example.com",
+		},
+		"single label": {
+			domain:         "localhost",
+			expectedDomain: "localhost-staging",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedDomain, suffixDomain(test.domain, "-staging"))
+		})
+	}
+}
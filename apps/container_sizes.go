@@ -0,0 +1,98 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// defaultContainerSize is the size of the container types scaled without
+// size for the first time
+const defaultContainerSize = "M"
+
+// ContainerSizes lists the available container sizes with their resources
+// and prices
+func ContainerSizes(ctx context.Context) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	sizes, err := c.ContainerSizesList(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list container sizes")
+	}
+	slices.SortFunc(sizes, func(a, b scalingo.ContainerSize) int {
+		return a.Ordinal - b.Ordinal
+	})
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Name", "Human Name", "Memory", "Swap", "CPU", "Hourly Price", "30 Days Price"})
+	for _, size := range sizes {
+		_ = t.Append([]string{
+			size.Name,
+			size.HumanName,
+			humanize.IBytes(uint64(size.Memory)),
+			humanize.IBytes(uint64(size.Swap)),
+			size.HumanCPU,
+			formatPrice(size.HourlyPrice),
+			formatPrice(size.ThirtydaysPrice),
+		})
+	}
+	_ = t.Render()
+	return nil
+}
+
+func containerSizesByName(ctx context.Context, c *scalingo.Client) (map[string]scalingo.ContainerSize, error) {
+	sizes, err := c.ContainerSizesList(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "list container sizes")
+	}
+	sizesByName := make(map[string]scalingo.ContainerSize, len(sizes))
+	for _, size := range sizes {
+		sizesByName[size.Name] = size
+	}
+	return sizesByName, nil
+}
+
+// formationMonthlyCost returns the price of the containers of the formation
+// for 30 days, in cents. The sizes without price are returned.
+func formationMonthlyCost(formation []scalingo.ContainerType, sizes map[string]scalingo.ContainerSize) (int, []string) {
+	cost := 0
+	var unknownSizes []string
+	for _, containerType := range formation {
+		if containerType.Amount == 0 {
+			continue
+		}
+		size, ok := sizes[containerType.Size]
+		if !ok {
+			if !slices.Contains(unknownSizes, containerType.Size) {
+				unknownSizes = append(unknownSizes, containerType.Size)
+			}
+			continue
+		}
+		cost += containerType.Amount * size.ThirtydaysPrice
+	}
+	return cost, unknownSizes
+}
+
+// formatPrice formats a price in cents
+func formatPrice(cents int) string {
+	return fmt.Sprintf("%.2f €", float64(cents)/100)
+}
+
+// formatPriceDelta formats a price difference in cents with its sign
+func formatPriceDelta(cents int) string {
+	if cents < 0 {
+		return "-" + formatPrice(-cents)
+	}
+	return "+" + formatPrice(cents)
+}
@@ -0,0 +1,48 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestTargetFormation(t *testing.T) {
+	current := []scalingo.ContainerType{
+		{Name: "web", Amount: 2, Size: "M"},
+		{Name: "worker", Amount: 1, Size: "S"},
+	}
+
+	target := targetFormation(current, []scalingo.ContainerType{
+		{Name: "web", Amount: 4, Size: "L"},
+		{Name: "worker", Amount: 0},
+		{Name: "clock", Amount: 1},
+	})
+
+	assert.Equal(t, []scalingo.ContainerType{
+		{Name: "web", Amount: 4, Size: "L"},
+		{Name: "worker", Amount: 0, Size: "S"},
+		{Name: "clock", Amount: 1, Size: "M"},
+	}, target)
+	assert.Equal(t, 2, current[0].Amount, "the current formation must not be modified")
+}
+
+func TestFormationMonthlyCost(t *testing.T) {
+	sizes := map[string]scalingo.ContainerSize{
+		"S": {Name: "S", ThirtydaysPrice: 720},
+		"M": {Name: "M", ThirtydaysPrice: 1440},
+	}
+
+	cost, unknownSizes := formationMonthlyCost([]scalingo.ContainerType{
+		{Name: "web", Amount: 2, Size: "M"},
+		{Name: "worker", Amount: 1, Size: "S"},
+		{Name: "clock", Amount: 0, Size: "M"},
+		{Name: "gpu", Amount: 1, Size: "GPU-XL"},
+	}, sizes)
+
+	assert.Equal(t, 3600, cost)
+	assert.Equal(t, []string{"GPU-XL"}, unknownSizes)
+	assert.Equal(t, "36.00 €", formatPrice(cost))
+	assert.Equal(t, "-7.20 €", formatPriceDelta(-720))
+}
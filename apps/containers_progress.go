@@ -0,0 +1,187 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	stdio "io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	scalePollInterval     = 2 * time.Second
+	containerStateCrashed = "crashed"
+)
+
+// containersProgress prints the changes of state of the containers of some
+// container types during an operation
+type containersProgress struct {
+	output stdio.Writer
+	types  []string
+	states map[string]string
+}
+
+func newContainersProgress(types []string) *containersProgress {
+	return &containersProgress{output: os.Stderr, types: types, states: map[string]string{}}
+}
+
+// update prints the containers whose state changed since the previous
+// update and returns the crashed containers
+func (p *containersProgress) update(containers []scalingo.Container) []string {
+	seen := map[string]bool{}
+	var crashed []string
+	for _, container := range containers {
+		if !slices.Contains(p.types, container.Type) {
+			continue
+		}
+		name := containerName(container)
+		seen[name] = true
+
+		previous, known := p.states[name]
+		if !known {
+			fmt.Fprintf(p.output, "  %s: %s\n", name, container.State)
+		} else if previous != container.State {
+			fmt.Fprintf(p.output, "  %s: %s → %s\n", name, previous, container.State)
+		}
+		p.states[name] = container.State
+		if container.State == containerStateCrashed {
+			crashed = append(crashed, name)
+		}
+	}
+
+	var stopped []string
+	for name := range p.states {
+		if !seen[name] {
+			stopped = append(stopped, name)
+		}
+	}
+	slices.Sort(stopped)
+	for _, name := range stopped {
+		fmt.Fprintf(p.output, "  %s: %s → stopped\n", name, p.states[name])
+		delete(p.states, name)
+	}
+	return crashed
+}
+
+// containerName returns the name of the container used in the scope of the
+// restarts, e.g. web-1
+func containerName(container scalingo.Container) string {
+	if container.Label != "" {
+		return container.Label
+	}
+	return fmt.Sprintf("%s-%d", container.Type, container.TypeIndex)
+}
+
+// waitOperationWithProgress waits for the end of an operation while
+// displaying the progress of the containers
+func waitOperationWithProgress(ctx context.Context, c *scalingo.Client, app, operationURL string, progress *containersProgress) error {
+	opURL, err := url.Parse(operationURL)
+	if err != nil {
+		return errors.Wrap(ctx, err, "parse url of operation")
+	}
+	opID := filepath.Base(opURL.Path)
+
+	for {
+		containers, err := c.AppsContainersPs(ctx, app)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list containers")
+		}
+		progress.update(containers)
+
+		op, err := c.OperationsShow(ctx, app, opID)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "get operation %v", opID)
+		}
+		switch op.Status {
+		case scalingo.OperationStatusDone:
+			fmt.Printf("Done in %.3f seconds\n", op.ElapsedDuration())
+			return nil
+		case scalingo.OperationStatusError:
+			return errors.Newf(ctx, "operation %v failed: %s", op.ID, op.Error)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(scalePollInterval):
+		}
+	}
+}
+
+// watchCrashedContainers returns an error if a container of the scaled
+// types crashes during the grace period
+func watchCrashedContainers(ctx context.Context, c *scalingo.Client, app string, progress *containersProgress, gracePeriod time.Duration) error {
+	if gracePeriod <= 0 {
+		return nil
+	}
+	io.Statusf("Watching the containers for %s\n", gracePeriod)
+
+	deadline := time.Now().Add(gracePeriod)
+	for time.Now().Before(deadline) {
+		containers, err := c.AppsContainersPs(ctx, app)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list containers")
+		}
+		crashed := progress.update(containers)
+		if len(crashed) > 0 {
+			return errors.Newf(ctx, "containers crashed after the scale: %v", crashed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(scalePollInterval):
+		}
+	}
+	return nil
+}
+
+// rollbackScale scales the changed container types back to their previous
+// amount and size, and enables the autoscalers disabled by the scale again.
+// The container types which did not exist are scaled to 0.
+func rollbackScale(ctx context.Context, c *scalingo.Client, app string, previous, changes []scalingo.ContainerType, disabledAutoscalers []scalingo.Autoscaler) error {
+	params := &scalingo.AppsScaleParams{}
+	var types []string
+	for _, change := range changes {
+		restored := scalingo.ContainerType{Name: change.Name, Amount: 0}
+		i := slices.IndexFunc(previous, func(containerType scalingo.ContainerType) bool {
+			return containerType.Name == change.Name
+		})
+		if i != -1 {
+			restored = scalingo.ContainerType{Name: change.Name, Amount: previous[i].Amount, Size: previous[i].Size}
+		}
+		params.Containers = append(params.Containers, restored)
+		types = append(types, change.Name)
+	}
+
+	io.Status("Restoring the previous formation:")
+	for _, containerType := range params.Containers {
+		fmt.Println(io.Indent(fmt.Sprintf("%s: %d - %s", containerType.Name, containerType.Amount, containerType.Size), 2))
+	}
+	_, operationURL, err := c.AppsScale(ctx, app, params)
+	if err != nil {
+		return errors.Wrap(ctx, err, "scale to the previous formation")
+	}
+	err = waitOperationWithProgress(ctx, c, app, operationURL, newContainersProgress(types))
+	if err != nil {
+		return err
+	}
+
+	for _, autoscaler := range disabledAutoscalers {
+		_, err := c.AutoscalerUpdate(ctx, app, autoscaler.ID, scalingo.AutoscalerUpdateParams{Disabled: utils.BoolPtr(false)})
+		if err != nil {
+			io.Warningf("Fail to enable the autoscaler of %s again (%v), run 'scalingo --app %s autoscalers-enable %s'\n", autoscaler.ContainerType, err, app, autoscaler.ContainerType)
+			continue
+		}
+		io.Statusf("The autoscaler of %s has been enabled again\n", autoscaler.ContainerType)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package apps
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestContainersProgress_Update(t *testing.T) {
+	output := &bytes.Buffer{}
+	progress := &containersProgress{output: output, types: []string{"web"}, states: map[string]string{}}
+
+	crashed := progress.update([]scalingo.Container{
+		{Type: "web", TypeIndex: 1, State: "running"},
+		{Type: "web", TypeIndex: 2, State: "starting"},
+		{Type: "worker", TypeIndex: 1, State: "running"},
+	})
+	assert.Empty(t, crashed)
+	assert.Equal(t, "  web-1: running\n  web-2: starting\n", output.String())
+
+	output.Reset()
+	crashed = progress.update([]scalingo.Container{
+		{Type: "web", TypeIndex: 2, State: "crashed"},
+	})
+	assert.Equal(t, []string{"web-2"}, crashed)
+	assert.Equal(t, "  web-2: starting → crashed\n  web-1: running → stopped\n", output.String())
+}
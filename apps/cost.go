@@ -0,0 +1,121 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const costConcurrency = 5
+
+type appCost struct {
+	App       string
+	Formation []scalingo.ContainerType
+	// Cost is the price of the containers for 30 days, in cents
+	Cost         int
+	UnknownSizes []string
+}
+
+// Cost estimates the price of the containers of all the apps for 30 days,
+// from their current formation. The addons are not included.
+func Cost(ctx context.Context, projectSlug string) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	appsList, err := c.AppsList(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list apps")
+	}
+	appsList = FilterByProject(appsList, projectSlug)
+	if len(appsList) == 0 {
+		io.Status("No app found")
+		return nil
+	}
+	sizes, err := containerSizesByName(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	costs := make([]appCost, len(appsList))
+	errs := make([]error, len(appsList))
+	semaphore := make(chan struct{}, costConcurrency)
+	wg := &sync.WaitGroup{}
+	for i, app := range appsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			formation, err := c.AppsContainerTypes(ctx, app.Name)
+			if err != nil {
+				errs[i] = errors.Wrapf(ctx, err, "list container types of %s", app.Name)
+				return
+			}
+			cost, unknownSizes := formationMonthlyCost(formation, sizes)
+			costs[i] = appCost{App: app.Name, Formation: formation, Cost: cost, UnknownSizes: unknownSizes}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	slices.SortFunc(costs, func(a, b appCost) int {
+		if a.Cost != b.Cost {
+			return b.Cost - a.Cost
+		}
+		return strings.Compare(a.App, b.App)
+	})
+
+	total := 0
+	var unknownSizes []string
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"App", "Containers", "30 Days Cost"})
+	for _, cost := range costs {
+		total += cost.Cost
+		for _, size := range cost.UnknownSizes {
+			if !slices.Contains(unknownSizes, size) {
+				unknownSizes = append(unknownSizes, size)
+			}
+		}
+		_ = t.Append([]string{cost.App, formatFormation(cost.Formation), formatPrice(cost.Cost)})
+	}
+	t.Footer([]string{"Total", "", formatPrice(total)})
+	_ = t.Render()
+
+	if len(unknownSizes) > 0 {
+		io.Warningf("Unknown container sizes: %s, their cost is not estimated\n", strings.Join(unknownSizes, ", "))
+	}
+	io.Info("The estimation only includes the containers, not the addons")
+	return nil
+}
+
+// formatFormation formats the running container types as <type>:<amount>:<size>
+func formatFormation(formation []scalingo.ContainerType) string {
+	var types []string
+	for _, containerType := range formation {
+		if containerType.Amount == 0 {
+			continue
+		}
+		types = append(types, fmt.Sprintf("%s:%d:%s", containerType.Name, containerType.Amount, containerType.Size))
+	}
+	if len(types) == 0 {
+		return "-"
+	}
+	return strings.Join(types, " ")
+}
@@ -0,0 +1,139 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const DefaultFormationProfilesFile = "profiles.yml"
+
+// ReadFormationProfile reads a formation profile from a YAML file listing
+// the amount and the optional size of container types by profile name:
+//
+//	night:
+//	  worker: 10:L
+//	day:
+//	  worker: 2:M
+//	  web: 2
+func ReadFormationProfile(ctx context.Context, path, name string) ([]scalingo.ContainerType, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read %s", path)
+	}
+
+	var profiles map[string]map[string]string
+	err = yaml.Unmarshal(content, &profiles)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse %s", path)
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for name := range profiles {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return nil, errors.Newf(ctx, "profile '%s' not found in %s, available profiles: %s", name, path, strings.Join(names, ", "))
+	}
+	if len(profile) == 0 {
+		return nil, errors.Newf(ctx, "profile '%s' has no container type", name)
+	}
+
+	formation := make([]scalingo.ContainerType, 0, len(profile))
+	for containerType, value := range profile {
+		amount, size, _ := strings.Cut(value, ":")
+		n, err := strconv.Atoi(amount)
+		if err != nil || n < 0 {
+			return nil, errors.Newf(ctx, "invalid formation '%s' for %s in profile '%s', format is <amount>[:<size>]", value, containerType, name)
+		}
+		formation = append(formation, scalingo.ContainerType{Name: containerType, Amount: n, Size: size})
+	}
+	slices.SortFunc(formation, func(a, b scalingo.ContainerType) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return formation, nil
+}
+
+// ScaleToProfile scales the application to the formation of the profile.
+// All the container types are scaled at once.
+func ScaleToProfile(ctx context.Context, app, path, name string, opts ScaleOpts) error {
+	formation, err := ReadFormationProfile(ctx, path, name)
+	if err != nil {
+		return err
+	}
+
+	types := make([]string, 0, len(formation))
+	for _, containerType := range formation {
+		t := fmt.Sprintf("%s:%d", containerType.Name, containerType.Amount)
+		if containerType.Size != "" {
+			t += ":" + containerType.Size
+		}
+		types = append(types, t)
+	}
+	io.Statusf("Applying the formation profile '%s'\n", name)
+	return Scale(ctx, app, types, opts)
+}
+
+// DiffProfile displays the container types whose current formation differs
+// from the formation of the profile
+func DiffProfile(ctx context.Context, app, path, name string) error {
+	profile, err := ReadFormationProfile(ctx, path, name)
+	if err != nil {
+		return err
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+	current, err := c.AppsContainerTypes(ctx, app)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list container types")
+	}
+	target := targetFormation(current, profile)
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Type", "Current", "Profile " + name})
+	differences := 0
+	for i, targetType := range target {
+		currentType := scalingo.ContainerType{Name: targetType.Name, Size: targetType.Size}
+		if i < len(current) {
+			currentType = current[i]
+		}
+		if currentType.Amount == targetType.Amount && currentType.Size == targetType.Size {
+			continue
+		}
+		differences++
+		_ = t.Append([]string{
+			targetType.Name,
+			fmt.Sprintf("%d:%s", currentType.Amount, currentType.Size),
+			fmt.Sprintf("%d:%s", targetType.Amount, targetType.Size),
+		})
+	}
+	if differences == 0 {
+		io.Statusf("The formation of %s matches the profile '%s'\n", app, name)
+		return nil
+	}
+	_ = t.Render()
+
+	sizes, err := containerSizesByName(ctx, c)
+	if err != nil {
+		return err
+	}
+	currentCost, _ := formationMonthlyCost(current, sizes)
+	targetCost, _ := formationMonthlyCost(target, sizes)
+	io.Infof("Estimated cost for 30 days: %s → %s (%s)\n", formatPrice(currentCost), formatPrice(targetCost), formatPriceDelta(targetCost-currentCost))
+	return nil
+}
@@ -0,0 +1,57 @@
+package apps
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestReadFormationProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.yml")
+	err := os.WriteFile(path, []byte("night:\n  worker: 10:L\n  web: 2\nday: {worker: 2:M}\nbroken: {worker: ten}\n"), 0o600)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		profile           string
+		expectedFormation []scalingo.ContainerType
+		expectedError     string
+	}{
+		"profile with and without sizes": {
+			profile: "night",
+			expectedFormation: []scalingo.ContainerType{
+				{Name: "web", Amount: 2},
+				{Name: "worker", Amount: 10, Size: "L"},
+			},
+		},
+		"flow mapping": {
+			profile:           "day",
+			expectedFormation: []scalingo.ContainerType{{Name: "worker", Amount: 2, Size: "M"}},
+		},
+		"invalid amount": {
+			profile:       "broken",
+			expectedError: "invalid formation 'ten' for worker in profile 'broken'",
+		},
+		"unknown profile": {
+			profile:       "weekend",
+			expectedError: "available profiles: broken, day, night",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			formation, err := ReadFormationProfile(t.Context(), path, test.profile)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedFormation, formation)
+		})
+	}
+}
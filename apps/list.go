@@ -20,7 +20,7 @@ func List(ctx context.Context, renderer renderer.Renderer[[]*scalingo.App], proj
 		return errors.Wrap(ctx, err, "list apps")
 	}
 
-	filteredApps := filterAppsByProject(apps, projectSlug)
+	filteredApps := FilterByProject(apps, projectSlug)
 	renderer.SetData(ctx, filteredApps)
 
 	err = renderer.Render(ctx)
@@ -31,7 +31,10 @@ func List(ctx context.Context, renderer renderer.Renderer[[]*scalingo.App], proj
 	return nil
 }
 
-func filterAppsByProject(apps []*scalingo.App, projectSlug string) []*scalingo.App {
+// FilterByProject returns the apps of the project identified by its slug
+// (<ownerUsername>/<projectName>). All the apps are returned if the slug is
+// empty.
+func FilterByProject(apps []*scalingo.App, projectSlug string) []*scalingo.App {
 	if projectSlug == "" {
 		return apps
 	}
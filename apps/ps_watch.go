@@ -0,0 +1,247 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	stdio "io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	PsFormatTable = "table"
+	PsFormatJSON  = "json"
+
+	DefaultPsWatchInterval = 5 * time.Second
+	MinPsWatchInterval     = 2 * time.Second
+
+	// oomMemoryRatio is the memory usage ratio above which a crash is
+	// reported as a possible out of memory
+	oomMemoryRatio = 0.95
+)
+
+const (
+	ContainerEventStarted   = "started"
+	ContainerEventStopped   = "stopped"
+	ContainerEventFinished  = "finished"
+	ContainerEventRestarted = "restarted"
+	ContainerEventCrashed   = "crashed"
+	ContainerEventState     = "state_changed"
+)
+
+type PsWatchOpts struct {
+	Interval time.Duration
+	Format   string
+}
+
+// ContainerEvent is a change in the lifecycle of a container. The restarts
+// are counted since the beginning of the watch.
+type ContainerEvent struct {
+	Time          time.Time `json:"time"`
+	App           string    `json:"app"`
+	Container     string    `json:"container"`
+	Type          string    `json:"type"`
+	Event         string    `json:"event"`
+	State         string    `json:"state"`
+	PreviousState string    `json:"previous_state,omitempty"`
+	Restarts      int       `json:"restarts"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	Hint          string    `json:"hint,omitempty"`
+}
+
+// PsWatch polls the containers of the application and prints their changes
+// as events until the context is canceled. With the JSON format, an event is
+// printed per line.
+func PsWatch(ctx context.Context, app string, opts PsWatchOpts) error {
+	if opts.Interval < MinPsWatchInterval {
+		opts.Interval = DefaultPsWatchInterval
+	}
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	containers, err := c.AppsContainersPs(ctx, app)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list the application containers")
+	}
+	watcher := newContainersWatcher(app)
+	watcher.diff(containers, time.Now())
+	if opts.Format != PsFormatJSON {
+		err = Ps(ctx, app)
+		if err != nil {
+			return err
+		}
+		io.Statusf("Watching the containers of %s every %s, press Ctrl-C to stop\n", app, opts.Interval)
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// The memory usage is only used to hint at out of memory crashes,
+		// the watch goes on without it
+		stats, err := c.AppsStats(ctx, app)
+		if err == nil {
+			watcher.updateMemory(stats.Stats)
+		}
+		containers, err := c.AppsContainersPs(ctx, app)
+		if err != nil {
+			// The events are printed on the standard output, the failures are
+			// written on the error output not to break the JSON lines
+			fmt.Fprintf(os.Stderr, "  /!\\  Fail to list the containers: %v\n", err)
+			continue
+		}
+		for _, event := range watcher.diff(containers, time.Now()) {
+			err = writeContainerEvent(os.Stdout, event, opts.Format)
+			if err != nil {
+				return errors.Wrap(ctx, err, "write container event")
+			}
+		}
+	}
+}
+
+// containersWatcher keeps the last known containers of an application to
+// compute the events between two polls
+type containersWatcher struct {
+	app         string
+	initialized bool
+	containers  map[string]scalingo.Container
+	restarts    map[string]int
+	memory      map[string]float64
+}
+
+func newContainersWatcher(app string) *containersWatcher {
+	return &containersWatcher{
+		app:        app,
+		containers: map[string]scalingo.Container{},
+		restarts:   map[string]int{},
+		memory:     map[string]float64{},
+	}
+}
+
+// updateMemory keeps the memory usage ratio of the containers
+func (w *containersWatcher) updateMemory(stats []*scalingo.ContainerStat) {
+	for _, stat := range stats {
+		if stat.MemoryLimit > 0 {
+			w.memory[stat.ID] = float64(stat.MemoryUsage) / float64(stat.MemoryLimit)
+		}
+	}
+}
+
+// diff returns the events between the last known containers and the given
+// ones, ordered by container name. The first call only records the
+// containers.
+func (w *containersWatcher) diff(containers []scalingo.Container, now time.Time) []ContainerEvent {
+	first := !w.initialized
+	w.initialized = true
+
+	var events []ContainerEvent
+	current := make(map[string]scalingo.Container, len(containers))
+	for _, container := range containers {
+		name := containerName(container)
+		current[name] = container
+		if first {
+			continue
+		}
+
+		previous, known := w.containers[name]
+		switch {
+		case !known:
+			events = append(events, w.event(now, container, ContainerEventStarted, ""))
+		case previous.ID != container.ID || (previous.State == containerStateCrashed && container.State != containerStateCrashed):
+			w.restarts[name]++
+			events = append(events, w.event(now, container, ContainerEventRestarted, previous.State))
+		case previous.State != container.State && container.State == containerStateCrashed:
+			event := w.event(now, container, ContainerEventCrashed, previous.State)
+			if w.memory[name] >= oomMemoryRatio {
+				event.Hint = fmt.Sprintf("memory at %.0f%% of the limit, OOM?", w.memory[name]*100)
+			}
+			events = append(events, event)
+		case previous.State != container.State:
+			events = append(events, w.event(now, container, ContainerEventState, previous.State))
+		}
+	}
+
+	for name, previous := range w.containers {
+		if _, ok := current[name]; ok {
+			continue
+		}
+		eventType := ContainerEventStopped
+		if slices.Contains(oneOffContainerTypes, previous.Type) {
+			eventType = ContainerEventFinished
+		}
+		event := w.event(now, previous, eventType, previous.State)
+		event.State = "stopped"
+		events = append(events, event)
+		delete(w.restarts, name)
+		delete(w.memory, name)
+	}
+
+	w.containers = current
+	slices.SortFunc(events, func(a, b ContainerEvent) int {
+		return strings.Compare(a.Container, b.Container)
+	})
+	return events
+}
+
+func (w *containersWatcher) event(now time.Time, container scalingo.Container, eventType, previousState string) ContainerEvent {
+	name := containerName(container)
+	var uptime time.Duration
+	if container.CreatedAt != nil {
+		uptime = now.Sub(*container.CreatedAt)
+	}
+	return ContainerEvent{
+		Time:          now,
+		App:           w.app,
+		Container:     name,
+		Type:          container.Type,
+		Event:         eventType,
+		State:         container.State,
+		PreviousState: previousState,
+		Restarts:      w.restarts[name],
+		UptimeSeconds: int64(uptime.Seconds()),
+	}
+}
+
+func writeContainerEvent(w stdio.Writer, event ContainerEvent, format string) error {
+	if format == PsFormatJSON {
+		return json.NewEncoder(w).Encode(event)
+	}
+	_, err := fmt.Fprintln(w, formatContainerEvent(event))
+	return err
+}
+
+// formatContainerEvent formats an event as a human readable line, e.g.
+// "2026/10/19 10:42:00 worker-1 crashed (memory at 98% of the limit, OOM?) - restarts: 0, uptime: 2h3m0s"
+func formatContainerEvent(event ContainerEvent) string {
+	var description string
+	switch event.Event {
+	case ContainerEventState:
+		description = fmt.Sprintf("%s → %s", event.PreviousState, event.State)
+	default:
+		description = event.Event
+	}
+	if event.Hint != "" {
+		description += " (" + event.Hint + ")"
+	}
+	uptime := time.Duration(event.UptimeSeconds) * time.Second
+	return fmt.Sprintf(
+		"%s %s %s - restarts: %d, uptime: %s",
+		event.Time.Format(utils.TimeFormat), event.Container, description, event.Restarts, uptime,
+	)
+}
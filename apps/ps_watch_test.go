@@ -0,0 +1,82 @@
+package apps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestContainersWatcher_Diff(t *testing.T) {
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	createdAt := now.Add(-time.Hour)
+	container := func(containerType string, index int, id, state string) scalingo.Container {
+		return scalingo.Container{ID: id, Type: containerType, TypeIndex: index, State: state, CreatedAt: &createdAt}
+	}
+	initial := []scalingo.Container{
+		container("web", 1, "ctr-1", "running"),
+		container("worker", 1, "ctr-2", "running"),
+		container("one-off", 1234, "ctr-3", "running"),
+	}
+
+	tests := map[string]struct {
+		containers     []scalingo.Container
+		memory         []*scalingo.ContainerStat
+		expectedEvents []ContainerEvent
+	}{
+		"no change": {
+			containers: initial,
+		},
+		"a container is started and a one-off container finishes": {
+			containers: []scalingo.Container{
+				container("web", 1, "ctr-1", "running"),
+				container("web", 2, "ctr-4", "starting"),
+				container("worker", 1, "ctr-2", "running"),
+			},
+			expectedEvents: []ContainerEvent{
+				{Container: "one-off-1234", Type: "one-off", Event: ContainerEventFinished, State: "stopped", PreviousState: "running", UptimeSeconds: 3600},
+				{Container: "web-2", Type: "web", Event: ContainerEventStarted, State: "starting", UptimeSeconds: 3600},
+			},
+		},
+		"a container crashes close to its memory limit": {
+			containers: []scalingo.Container{
+				container("web", 1, "ctr-1", "running"),
+				container("worker", 1, "ctr-2", "crashed"),
+				container("one-off", 1234, "ctr-3", "running"),
+			},
+			memory: []*scalingo.ContainerStat{{ID: "worker-1", MemoryUsage: 98, MemoryLimit: 100}},
+			expectedEvents: []ContainerEvent{
+				{Container: "worker-1", Type: "worker", Event: ContainerEventCrashed, State: "crashed", PreviousState: "running", UptimeSeconds: 3600, Hint: "memory at 98% of the limit, OOM?"},
+			},
+		},
+		"a container is replaced": {
+			containers: []scalingo.Container{
+				container("web", 1, "ctr-5", "running"),
+				container("worker", 1, "ctr-2", "running"),
+				container("one-off", 1234, "ctr-3", "running"),
+			},
+			expectedEvents: []ContainerEvent{
+				{Container: "web-1", Type: "web", Event: ContainerEventRestarted, State: "running", PreviousState: "running", Restarts: 1, UptimeSeconds: 3600},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			watcher := newContainersWatcher("my-app")
+			require.Empty(t, watcher.diff(initial, now))
+			watcher.updateMemory(test.memory)
+
+			events := watcher.diff(test.containers, now)
+
+			for i := range test.expectedEvents {
+				test.expectedEvents[i].Time = now
+				test.expectedEvents[i].App = "my-app"
+			}
+			assert.Equal(t, test.expectedEvents, events)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	containerStateRunning = "running"
+	healthCheckTimeout    = 5 * time.Second
+)
+
+// oneOffContainerTypes are not restarted by a rolling restart
+var oneOffContainerTypes = []string{"one-off", "postdeploy"}
+
+type RollingRestartOpts struct {
+	// Types are the container types to restart, all of them if empty
+	Types []string
+	// Batch is the number of containers restarted at once
+	Batch int
+	// WaitHealthy is the maximal duration for the containers of a batch to
+	// be running and the health URL to respond successfully
+	WaitHealthy time.Duration
+	HealthURL   string
+}
+
+// RollingRestart restarts the containers batch by batch. The next batch is
+// restarted once the containers of the previous batch are running and the
+// optional health URL responds successfully. The restart stops with an
+// error if a batch doesn't come back healthy.
+func RollingRestart(ctx context.Context, app string, opts RollingRestartOpts) error {
+	if opts.Batch < 1 {
+		opts.Batch = 1
+	}
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	containers, err := c.AppsContainersPs(ctx, app)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list containers")
+	}
+	containers = rollingRestartContainers(containers, opts.Types)
+	if len(containers) == 0 {
+		return errors.New(ctx, "no container to restart")
+	}
+	var names, types []string
+	for _, container := range containers {
+		names = append(names, containerName(container))
+		if !slices.Contains(types, container.Type) {
+			types = append(types, container.Type)
+		}
+	}
+	batches := slices.Collect(slices.Chunk(names, opts.Batch))
+
+	for i, batch := range batches {
+		io.Statusf("Restarting batch %d/%d: %s\n", i+1, len(batches), strings.Join(batch, ", "))
+		operationURL, err := c.AppsRestart(ctx, app, &scalingo.AppsRestartParams{Scope: batch})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "restart batch %d", i+1)
+		}
+		err = waitOperationWithProgress(ctx, c, app, operationURL, newContainersProgress(types))
+		if err != nil {
+			return errors.Wrapf(ctx, err, "wait for the restart of batch %d", i+1)
+		}
+		err = waitHealthyBatch(ctx, c, app, batch, opts)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "batch %d did not come back healthy, the remaining containers have not been restarted", i+1)
+		}
+	}
+
+	fmt.Println("Your application has been restarted.")
+	return nil
+}
+
+// rollingRestartContainers returns the containers of the types ordered by
+// type and index. The one-off containers are ignored.
+func rollingRestartContainers(containers []scalingo.Container, types []string) []scalingo.Container {
+	containers = slices.DeleteFunc(slices.Clone(containers), func(container scalingo.Container) bool {
+		if slices.Contains(oneOffContainerTypes, container.Type) {
+			return true
+		}
+		return len(types) > 0 && !slices.Contains(types, container.Type)
+	})
+	slices.SortFunc(containers, func(a, b scalingo.Container) int {
+		if a.Type != b.Type {
+			return strings.Compare(a.Type, b.Type)
+		}
+		return a.TypeIndex - b.TypeIndex
+	})
+	return containers
+}
+
+// waitHealthyBatch waits for the containers of the batch to be running and
+// for the health URL to respond successfully
+func waitHealthyBatch(ctx context.Context, c *scalingo.Client, app string, batch []string, opts RollingRestartOpts) error {
+	deadline := time.Now().Add(opts.WaitHealthy)
+	for {
+		containers, err := c.AppsContainersPs(ctx, app)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list containers")
+		}
+		reason, crashed := unhealthyReason(containers, batch)
+		if crashed {
+			return errors.New(ctx, reason)
+		}
+		if reason == "" && opts.HealthURL != "" {
+			reason = checkHealthURL(ctx, opts.HealthURL)
+		}
+		if reason == "" {
+			io.Status("The batch is healthy")
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Newf(ctx, "still unhealthy after %s: %s", opts.WaitHealthy, reason)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(scalePollInterval):
+		}
+	}
+}
+
+// unhealthyReason returns why the containers of the batch are not healthy,
+// an empty string if they are all running, and whether a container crashed
+func unhealthyReason(containers []scalingo.Container, batch []string) (string, bool) {
+	states := map[string]string{}
+	for _, container := range containers {
+		states[containerName(container)] = container.State
+	}
+	var reasons []string
+	crashed := false
+	for _, name := range batch {
+		state, ok := states[name]
+		if !ok {
+			state = "missing"
+		}
+		if state != containerStateRunning {
+			reasons = append(reasons, fmt.Sprintf("%s is %s", name, state))
+		}
+		crashed = crashed || state == containerStateCrashed
+	}
+	return strings.Join(reasons, ", "), crashed
+}
+
+// checkHealthURL returns why the health URL is not healthy, an empty string
+// if it responds with a success or redirection status
+func checkHealthURL(ctx context.Context, healthURL string) string {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return fmt.Sprintf("invalid health URL: %v", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Sprintf("health check failed: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Sprintf("health check returned %s", res.Status)
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestRollingRestartContainers(t *testing.T) {
+	containers := []scalingo.Container{
+		{Type: "worker", TypeIndex: 1},
+		{Type: "web", TypeIndex: 2},
+		{Type: "one-off", TypeIndex: 1234},
+		{Type: "web", TypeIndex: 1},
+	}
+
+	tests := map[string]struct {
+		types         []string
+		expectedNames []string
+	}{
+		"all container types": {
+			expectedNames: []string{"web-1", "web-2", "worker-1"},
+		},
+		"a container type": {
+			types:         []string{"web"},
+			expectedNames: []string{"web-1", "web-2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var names []string
+			for _, container := range rollingRestartContainers(containers, test.types) {
+				names = append(names, containerName(container))
+			}
+			assert.Equal(t, test.expectedNames, names)
+		})
+	}
+}
+
+func TestUnhealthyReason(t *testing.T) {
+	containers := []scalingo.Container{
+		{Type: "web", TypeIndex: 1, State: "running"},
+		{Type: "web", TypeIndex: 2, State: "starting"},
+		{Type: "web", TypeIndex: 3, State: "crashed"},
+	}
+
+	reason, crashed := unhealthyReason(containers, []string{"web-1"})
+	assert.Empty(t, reason)
+	assert.False(t, crashed)
+
+	reason, crashed = unhealthyReason(containers, []string{"web-2", "web-4"})
+	assert.Equal(t, "web-2 is starting, web-4 is missing", reason)
+	assert.False(t, crashed)
+
+	reason, crashed = unhealthyReason(containers, []string{"web-1", "web-3"})
+	assert.Equal(t, "web-3 is crashed", reason)
+	assert.True(t, crashed)
+}
@@ -3,9 +3,14 @@ package apps
 import (
 	"context"
 	"fmt"
-
+	"os"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"golang.org/x/term"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
@@ -20,11 +25,66 @@ type ScaleRes struct {
 	Containers []scalingo.ContainerType `json:"containers"`
 }
 
-func Scale(ctx context.Context, app string, sync bool, types []string) error {
+type ScaleOpts struct {
+	Synchronous bool
+	// DryRun displays the current and target formations with their monthly
+	// cost without scaling the application
+	DryRun bool
+	// RollbackOnFailure restores the previous formation if the scale
+	// operation fails or if containers crash during the grace period. It
+	// implies a synchronous scale.
+	RollbackOnFailure bool
+	GracePeriod       time.Duration
+	// Yes skips the confirmation when an autoscaler would be disabled
+	Yes bool
+}
+
+// scaleArg is a container type to scale given as <type>:<amount>[:<size>].
+// The amount is relative to the current amount if it has a '-' or '+'
+// modificator.
+type scaleArg struct {
+	name        string
+	amount      int64
+	size        string
+	modificator byte
+}
+
+// parseScaleArgs parses the container types to scale. The size and the
+// modificator only apply to the container type they are given with.
+func parseScaleArgs(ctx context.Context, types []string) ([]scaleArg, error) {
+	args := make([]scaleArg, 0, len(types))
+	for _, t := range types {
+		splitT := strings.Split(t, ":")
+		if len(splitT) != 2 && len(splitT) != 3 {
+			return nil, errors.Newf(ctx, "%s is invalid, format is <type>:<amount>[:<size>]", t)
+		}
+		arg := scaleArg{name: splitT[0]}
+		typeAmount := splitT[1]
+		if len(splitT) == 3 {
+			arg.size = splitT[2]
+		}
+
+		if typeAmount != "" && (typeAmount[0] == '-' || typeAmount[0] == '+') {
+			arg.modificator = typeAmount[0]
+			typeAmount = typeAmount[1:]
+			if arg.size != "" {
+				return nil, errors.Newf(ctx, "%s is invalid, can't use relative modificator with size, change the size first", t)
+			}
+		}
+
+		amount, err := strconv.ParseInt(typeAmount, 10, 32)
+		if err != nil {
+			return nil, errors.Newf(ctx, "%s in %s should be an integer", typeAmount, t)
+		}
+		arg.amount = amount
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func Scale(ctx context.Context, app string, types []string, opts ScaleOpts) error {
 	var (
-		size           string
 		containerTypes []scalingo.ContainerType
-		modificator    byte
 		err            error
 	)
 
@@ -34,49 +94,38 @@ func Scale(ctx context.Context, app string, sync bool, types []string) error {
 	}
 	scaleParams := &scalingo.AppsScaleParams{}
 	typesWithAutoscaler := []string{}
+	// disabledAutoscalers are the enabled autoscalers disabled by the scale
+	var disabledAutoscalers []scalingo.Autoscaler
 	autoscalers, err := c.AutoscalersList(ctx, app)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "fail to list the autoscalers")
 	}
 
-	for _, t := range types {
-		splitT := strings.Split(t, ":")
-		if len(splitT) != 2 && len(splitT) != 3 {
-			return errors.Newf(ctx, "%s is invalid, format is <type>:<amount>[:<size>]", t)
-		}
-		typeName, typeAmount := splitT[0], splitT[1]
-		if len(splitT) == 3 {
-			size = splitT[2]
-		}
-
-		if typeAmount[0] == '-' || typeAmount[0] == '+' {
-			modificator = typeAmount[0]
-			typeAmount = typeAmount[1:]
-			if size != "" {
-				return errors.Newf(ctx, "%s is invalid, can't use relative modificator with size, change the size first", t)
-			}
-			if containerTypes == nil {
-				containerTypes, err = c.AppsContainerTypes(ctx, app)
-				if err != nil {
-					return errors.Wrapf(ctx, err, "fail to get list of running containers")
-				}
-				debug.Println("get container list", containerTypes)
+	scaleArgs, err := parseScaleArgs(ctx, types)
+	if err != nil {
+		return err
+	}
+	for _, arg := range scaleArgs {
+		typeName, modificator, amount := arg.name, arg.modificator, arg.amount
+		if (modificator != 0 || opts.DryRun || opts.RollbackOnFailure) && containerTypes == nil {
+			containerTypes, err = c.AppsContainerTypes(ctx, app)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "fail to get list of running containers")
 			}
-		}
-
-		amount, err := strconv.ParseInt(typeAmount, 10, 32)
-		if err != nil {
-			return errors.Newf(ctx, "%s in %s should be an integer", typeAmount, t)
+			debug.Println("get container list", containerTypes)
 		}
 
 		for _, a := range autoscalers {
 			if a.ContainerType == typeName {
 				typesWithAutoscaler = append(typesWithAutoscaler, typeName)
+				if !a.Disabled {
+					disabledAutoscalers = append(disabledAutoscalers, a)
+				}
 				break
 			}
 		}
 
-		newContainerConfig := scalingo.ContainerType{Name: typeName, Size: size}
+		newContainerConfig := scalingo.ContainerType{Name: typeName, Size: arg.size}
 		if modificator != 0 {
 			for _, container := range containerTypes {
 				if container.Name == typeName {
@@ -96,13 +145,22 @@ func Scale(ctx context.Context, app string, sync bool, types []string) error {
 		scaleParams.Containers = append(scaleParams.Containers, newContainerConfig)
 	}
 
+	if opts.DryRun {
+		if len(typesWithAutoscaler) > 0 {
+			io.Warningf("Manually scaling %s will disable the autoscaler\n", strings.Join(typesWithAutoscaler, ", "))
+		}
+		return displayScaleDryRun(ctx, c, containerTypes, targetFormation(containerTypes, scaleParams.Containers))
+	}
+
 	if len(typesWithAutoscaler) > 0 {
 		io.Warning(autoscaleDisableMessage(typesWithAutoscaler))
-
-		var confirm string
-		fmt.Scanln(&confirm)
-		if confirm != "y" && confirm != "Y" {
-			return errors.New(ctx, "You didn't confirm, aborting…")
+		if !opts.Yes {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				return errors.New(ctx, "confirmation required to disable the autoscaler, use --yes in non-interactive mode")
+			}
+			if !utils.Confirm("Do you confirm?") {
+				return errors.New(ctx, "You didn't confirm, aborting…")
+			}
 		}
 	}
 
@@ -123,7 +181,7 @@ func Scale(ctx context.Context, app string, sync bool, types []string) error {
 		}
 		// If error is Payment Required and user tries to exceed its free trial
 		return utils.AskAndStopFreeTrial(ctx, c, func() error {
-			return Scale(ctx, app, sync, types)
+			return Scale(ctx, app, types, opts)
 		})
 	}
 
@@ -132,23 +190,94 @@ func Scale(ctx context.Context, app string, sync bool, types []string) error {
 		fmt.Println(io.Indent(fmt.Sprintf("%s: %d - %s", ct.Name, ct.Amount, ct.Size), 2))
 	}
 
-	if !sync {
+	if !opts.Synchronous && !opts.RollbackOnFailure {
 		return nil
 	}
 
-	waiter, err := newOperationWaiterFromURL(ctx, app, operationURL)
-	if err != nil {
-		return errors.Wrap(ctx, err, "create operation waiter")
+	scaledTypes := make([]string, 0, len(scaleParams.Containers))
+	for _, containerType := range scaleParams.Containers {
+		scaledTypes = append(scaledTypes, containerType.Name)
+	}
+	progress := newContainersProgress(scaledTypes)
+	err = waitOperationWithProgress(ctx, c, app, operationURL, progress)
+	if err == nil && opts.RollbackOnFailure {
+		err = watchCrashedContainers(ctx, c, app, progress, opts.GracePeriod)
 	}
-	_, err = waiter.WaitOperation(ctx)
 	if err != nil {
-		return errors.Wrapf(ctx, err, "wait for the end of the scale operation")
+		if !opts.RollbackOnFailure {
+			return errors.Wrapf(ctx, err, "wait for the end of the scale operation")
+		}
+		io.Errorf("The scale failed: %v\n", err)
+		rollbackErr := rollbackScale(ctx, c, app, containerTypes, scaleParams.Containers, disabledAutoscalers)
+		if rollbackErr != nil {
+			return errors.Wrapf(ctx, rollbackErr, "restore the previous formation after the scale failure (%v)", err)
+		}
+		return errors.Wrap(ctx, err, "the previous formation has been restored")
 	}
 
 	fmt.Println("Your application has been scaled.")
 	return nil
 }
 
+// targetFormation returns the formation after applying the scale changes.
+// The container types scaled for the first time without size get the
+// default size.
+func targetFormation(current []scalingo.ContainerType, changes []scalingo.ContainerType) []scalingo.ContainerType {
+	target := slices.Clone(current)
+	for _, change := range changes {
+		i := slices.IndexFunc(target, func(containerType scalingo.ContainerType) bool {
+			return containerType.Name == change.Name
+		})
+		if i == -1 {
+			if change.Size == "" {
+				change.Size = defaultContainerSize
+			}
+			target = append(target, change)
+			continue
+		}
+		target[i].Amount = change.Amount
+		if change.Size != "" {
+			target[i].Size = change.Size
+		}
+	}
+	return target
+}
+
+func displayScaleDryRun(ctx context.Context, c *scalingo.Client, current, target []scalingo.ContainerType) error {
+	sizes, err := containerSizesByName(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Type", "Current", "Target", "Current Cost", "Target Cost"})
+	for i, targetType := range target {
+		currentType := scalingo.ContainerType{Name: targetType.Name, Size: targetType.Size}
+		if i < len(current) {
+			currentType = current[i]
+		}
+		currentCost, _ := formationMonthlyCost([]scalingo.ContainerType{currentType}, sizes)
+		targetCost, _ := formationMonthlyCost([]scalingo.ContainerType{targetType}, sizes)
+		_ = t.Append([]string{
+			targetType.Name,
+			fmt.Sprintf("%d:%s", currentType.Amount, currentType.Size),
+			fmt.Sprintf("%d:%s", targetType.Amount, targetType.Size),
+			formatPrice(currentCost),
+			formatPrice(targetCost),
+		})
+	}
+	_ = t.Render()
+
+	currentCost, _ := formationMonthlyCost(current, sizes)
+	targetCost, unknownSizes := formationMonthlyCost(target, sizes)
+	if len(unknownSizes) > 0 {
+		io.Warningf("Unknown container sizes: %s, their cost is not estimated\n", strings.Join(unknownSizes, ", "))
+	}
+	io.Infof("Estimated cost for 30 days: %s → %s (%s)\n", formatPrice(currentCost), formatPrice(targetCost), formatPriceDelta(targetCost-currentCost))
+	io.Info("Dry run: the application has not been scaled")
+	return nil
+}
+
 func formatContainerTypesError(ctx context.Context, c *scalingo.Client, app string, requestFailedError *http.RequestFailedError) error {
 	containerTypes, err := c.AppsContainerTypes(ctx, app)
 	if err != nil {
@@ -202,6 +331,6 @@ func autoscaleDisableMessage(typesWithAutoscaler []string) string {
 	} else {
 		msg += "it"
 	}
-	msg += " will disable the autoscaler. Do you confirm? (y/N)"
+	msg += " will disable the autoscaler."
 	return msg
 }
@@ -0,0 +1,63 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScaleArgs(t *testing.T) {
+	tests := map[string]struct {
+		types         []string
+		expectedArgs  []scaleArg
+		expectedError string
+	}{
+		"sized type followed by an unsized one": {
+			types: []string{"api:1:L", "web:2"},
+			expectedArgs: []scaleArg{
+				{name: "api", amount: 1, size: "L"},
+				{name: "web", amount: 2},
+			},
+		},
+		"relative amount after a sized type": {
+			types: []string{"api:1:L", "web:+2"},
+			expectedArgs: []scaleArg{
+				{name: "api", amount: 1, size: "L"},
+				{name: "web", amount: 2, modificator: '+'},
+			},
+		},
+		"absolute amount after a relative one": {
+			types: []string{"web:-1", "worker:3"},
+			expectedArgs: []scaleArg{
+				{name: "web", amount: 1, modificator: '-'},
+				{name: "worker", amount: 3},
+			},
+		},
+		"relative amount with size": {
+			types:         []string{"web:+1:L"},
+			expectedError: "can't use relative modificator with size",
+		},
+		"invalid format": {
+			types:         []string{"web"},
+			expectedError: "format is <type>:<amount>[:<size>]",
+		},
+		"empty amount": {
+			types:         []string{"web:"},
+			expectedError: "should be an integer",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			args, err := parseScaleArgs(t.Context(), test.types)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedArgs, args)
+		})
+	}
+}
@@ -2,8 +2,11 @@ package apps
 
 import (
 	"context"
+	"fmt"
 	"maps"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
@@ -52,11 +55,53 @@ func keepUniqueContainersWithNames(ctx context.Context, containers []scalingo.Co
 	return containersToKill
 }
 
-func SendSignal(ctx context.Context, appName string, signal string, containerNames []string) error {
-	if len(containerNames) == 0 {
-		return errors.New(ctx, "at least one container name should be given")
+// keepContainersWithLabels returns the containers whose label is one of the
+// given names. A name matching no container is an error.
+func keepContainersWithLabels(ctx context.Context, containers []scalingo.Container, labels []string) (map[string]scalingo.Container, error) {
+	containersWithLabels := map[string]scalingo.Container{}
+	for _, label := range labels {
+		index := slices.IndexFunc(containers, func(container scalingo.Container) bool {
+			return container.Label == label
+		})
+		if index == -1 {
+			return nil, errors.Newf(ctx, "'%v' did not match any container, give the name of a container like %v-1", label, label)
+		}
+		containersWithLabels[label] = containers[index]
 	}
-	if signal == "" {
+	return containersWithLabels, nil
+}
+
+const (
+	SignalKill = "SIGKILL"
+
+	containerReplaceTimeout = 5 * time.Minute
+)
+
+// terminatingSignals are the signals stopping the process of the container,
+// the containers receiving other signals are usually not replaced
+var terminatingSignals = []string{SignalKill, "SIGTERM", "SIGINT", "SIGQUIT"}
+
+type SendSignalOpts struct {
+	Signal string
+	// Containers are container names or container types
+	Containers []string
+	// Types are container types whose containers all receive the signal
+	Types []string
+	// ExactLabels only accepts exact container names in Containers, the
+	// container types are refused
+	ExactLabels bool
+	// Wait follows the containers receiving the signal until they are
+	// replaced by running containers, or gone for the one-off containers.
+	// With a signal not terminating the process, a container still running
+	// after the signal is not waited for.
+	Wait bool
+}
+
+func SendSignal(ctx context.Context, appName string, opts SendSignalOpts) error {
+	if len(opts.Containers) == 0 && len(opts.Types) == 0 {
+		return errors.New(ctx, "at least one container name or type should be given")
+	}
+	if opts.Signal == "" {
 		return errors.New(ctx, "signal must not be empty")
 	}
 
@@ -70,15 +115,106 @@ func SendSignal(ctx context.Context, appName string, signal string, containerNam
 		return errors.Wrapf(ctx, err, "fail to list the application containers to get the ID of the container to send the signal")
 	}
 
-	containersToKill := keepUniqueContainersWithNames(ctx, containers, containerNames)
+	var containersToKill map[string]scalingo.Container
+	if opts.ExactLabels {
+		containersToKill, err = keepContainersWithLabels(ctx, containers, opts.Containers)
+		if err != nil {
+			return err
+		}
+	} else {
+		containersToKill = keepUniqueContainersWithNames(ctx, containers, opts.Containers)
+	}
+	for _, containerType := range opts.Types {
+		matched := false
+		for _, container := range containers {
+			if container.Type == containerType {
+				containersToKill[container.Label] = container
+				matched = true
+			}
+		}
+		if !matched {
+			return errors.Newf(ctx, "no container of type '%v'", containerType)
+		}
+	}
 
-	for _, container := range containersToKill {
-		err := c.ContainersKill(ctx, appName, signal, container.ID)
+	var killed []scalingo.Container
+	for _, label := range slices.Sorted(maps.Keys(containersToKill)) {
+		container := containersToKill[label]
+		err := c.ContainersKill(ctx, appName, opts.Signal, container.ID)
 		if err != nil {
 			io.Errorf("Fail to send signal to container '%v' because of: %v\n", container.Label, err)
 			continue
 		}
-		io.Statusf("Sent signal '%v' to '%v' container.\n", signal, container.Label)
+		io.Statusf("Sent signal '%v' to '%v' container.\n", opts.Signal, container.Label)
+		killed = append(killed, container)
+	}
+
+	if !opts.Wait || len(killed) == 0 {
+		return nil
+	}
+	return waitContainersReplaced(ctx, c, appName, killed, slices.Contains(terminatingSignals, opts.Signal))
+}
+
+// waitContainersReplaced waits for the containers to be replaced by running
+// containers with the same labels. The one-off containers are not replaced,
+// they are waited for until they are gone. If the signal is not terminating,
+// the containers still running are not waited for.
+func waitContainersReplaced(ctx context.Context, c *scalingo.Client, appName string, killed []scalingo.Container, terminating bool) error {
+	io.Status("Waiting for the containers to be replaced...")
+	deadline := time.Now().Add(containerReplaceTimeout)
+	for {
+		containers, err := c.AppsContainersPs(ctx, appName)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list the application containers")
+		}
+		killed = slices.DeleteFunc(killed, func(container scalingo.Container) bool {
+			replaced, message := containerReplaced(containers, container, terminating)
+			if replaced {
+				io.Statusf("%v\n", message)
+			}
+			return replaced
+		})
+		if len(killed) == 0 {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			var labels []string
+			for _, container := range killed {
+				labels = append(labels, container.Label)
+			}
+			return errors.Newf(ctx, "containers not replaced after %v: %v", containerReplaceTimeout, strings.Join(labels, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(scalePollInterval):
+		}
+	}
+}
+
+// containerReplaced returns whether the killed container has been replaced
+// in the list of containers, with a message describing the replacement. If
+// the signal is not terminating, the killed container still running is
+// considered done.
+func containerReplaced(containers []scalingo.Container, killed scalingo.Container, terminating bool) (bool, string) {
+	for _, container := range containers {
+		if container.Label != killed.Label {
+			continue
+		}
+		if container.ID == killed.ID {
+			if !terminating && container.State == containerStateRunning {
+				return true, fmt.Sprintf("'%v' is still running after the signal", killed.Label)
+			}
+			return false, ""
+		}
+		if container.State == containerStateRunning {
+			return true, fmt.Sprintf("'%v' has been replaced by a running container", killed.Label)
+		}
+		return false, ""
+	}
+	if slices.Contains(oneOffContainerTypes, killed.Type) {
+		return true, fmt.Sprintf("'%v' is gone", killed.Label)
 	}
-	return nil
+	return false, ""
 }
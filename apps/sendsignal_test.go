@@ -0,0 +1,102 @@
+package apps
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestContainerReplaced(t *testing.T) {
+	web := scalingo.Container{ID: "ctr-1", Type: "web", Label: "web-1", State: "running"}
+	oneOff := scalingo.Container{ID: "ctr-2", Type: "one-off", Label: "one-off-1234", State: "running"}
+
+	tests := map[string]struct {
+		containers       []scalingo.Container
+		killed           scalingo.Container
+		terminating      bool
+		expectedReplaced bool
+	}{
+		"the killed container is still there": {
+			containers:  []scalingo.Container{web},
+			killed:      web,
+			terminating: true,
+		},
+		"the container is still running after a non-terminating signal": {
+			containers:       []scalingo.Container{web},
+			killed:           web,
+			expectedReplaced: true,
+		},
+		"the container is stopping after a non-terminating signal": {
+			containers: []scalingo.Container{{ID: "ctr-1", Type: "web", Label: "web-1", State: "stopping"}},
+			killed:     web,
+		},
+		"the new container is starting": {
+			containers: []scalingo.Container{{ID: "ctr-3", Type: "web", Label: "web-1", State: "starting"}},
+			killed:     web,
+		},
+		"the new container is running": {
+			containers:       []scalingo.Container{{ID: "ctr-3", Type: "web", Label: "web-1", State: "running"}},
+			killed:           web,
+			expectedReplaced: true,
+		},
+		"the container is not recreated yet": {
+			killed: web,
+		},
+		"the one-off container is gone": {
+			containers:       []scalingo.Container{web},
+			killed:           oneOff,
+			expectedReplaced: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			replaced, _ := containerReplaced(test.containers, test.killed, test.terminating)
+			assert.Equal(t, test.expectedReplaced, replaced)
+		})
+	}
+}
+
+func TestKeepContainersWithLabels(t *testing.T) {
+	containers := []scalingo.Container{
+		{ID: "ctr-1", Type: "web", Label: "web-1"},
+		{ID: "ctr-2", Type: "web", Label: "web-2"},
+	}
+
+	tests := map[string]struct {
+		labels             []string
+		expectedContainers []string
+		expectedError      string
+	}{
+		"exact labels": {
+			labels:             []string{"web-2"},
+			expectedContainers: []string{"web-2"},
+		},
+		"container type": {
+			labels:        []string{"web"},
+			expectedError: "'web' did not match any container",
+		},
+		"unknown container": {
+			labels:        []string{"web-1", "web-3"},
+			expectedError: "'web-3' did not match any container",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			kept, err := keepContainersWithLabels(t.Context(), containers, test.labels)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.ElementsMatch(t, test.expectedContainers, slices.Collect(maps.Keys(kept)))
+		})
+	}
+}
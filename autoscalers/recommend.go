@@ -0,0 +1,340 @@
+package autoscalers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/metrics"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	// defaultCPUTarget keeps some headroom for the spikes between two
+	// autoscaling decisions
+	defaultCPUTarget = 0.7
+	// maxContainersHeadroom is applied to the highest amount of containers
+	// needed over the period
+	maxContainersHeadroom = 1.2
+	// minContainersPercentile ignores the lowest load points when
+	// computing the minimal amount of containers
+	minContainersPercentile = 0.05
+	// idleCPU is the CPU usage under which the RPM to CPU ratio is not
+	// significant
+	idleCPU = 0.05
+)
+
+// RecommendMetrics are the metrics the recommendations are computed for
+var RecommendMetrics = []string{scalingo.MetricCPU, scalingo.MetricRouterRPMPerContainer}
+
+type RecommendOpts struct {
+	ContainerType string
+	Since         time.Duration
+	// Metric is the metric of the recommendation to apply, the metric of
+	// the current autoscaler or CPU if empty
+	Metric string
+	Apply  bool
+}
+
+// recommendation is the configuration of an autoscaler
+type recommendation struct {
+	Metric        string
+	Target        float64
+	MinContainers int
+	MaxContainers int
+}
+
+// loadPoint is the load of a container type at a point in time. The
+// metrics are the averages per container.
+type loadPoint struct {
+	Time       time.Time
+	Containers int
+	CPU        float64
+	RPM        float64
+}
+
+// replayResult is the amount of containers which would have run over the
+// period with an autoscaler configuration
+type replayResult struct {
+	AverageContainers float64
+	PeakContainers    int
+	// SaturatedRatio is the share of the period during which more
+	// containers than the maximum were needed
+	SaturatedRatio float64
+}
+
+// Recommend analyses the CPU and RPM per container metrics of a container
+// type over a period, and suggests autoscaler configurations with a replay
+// of the amount of containers which would have run. The CPU usage is
+// expressed as a ratio of the container CPU, as the autoscaler target.
+func Recommend(ctx context.Context, app string, opts RecommendOpts) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "fail to get Scalingo client")
+	}
+
+	containerTypes, err := c.AppsContainerTypes(ctx, app)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "list container types of %s", app)
+	}
+	idx := slices.IndexFunc(containerTypes, func(containerType scalingo.ContainerType) bool {
+		return containerType.Name == opts.ContainerType
+	})
+	if idx == -1 {
+		return errors.Newf(ctx, "container type %s not found on app %s", opts.ContainerType, app)
+	}
+	currentAmount := containerTypes[idx].Amount
+
+	current, err := getFromContainerType(ctx, c, app, opts.ContainerType)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return errors.Wrapf(ctx, err, "find autoscaler for container type %s on app %s", opts.ContainerType, app)
+	}
+	hasAutoscaler := err == nil
+
+	points, err := fetchLoad(ctx, c, app, opts, currentAmount)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return errors.Newf(ctx, "no CPU metric for the %s containers over the last %v", opts.ContainerType, opts.Since)
+	}
+
+	recommendations := recommend(points)
+	metric := opts.Metric
+	if metric == "" && hasAutoscaler && slices.Contains(RecommendMetrics, current.Metric) {
+		metric = current.Metric
+	}
+	if metric == "" {
+		metric = scalingo.MetricCPU
+	}
+	selected := slices.IndexFunc(recommendations, func(r recommendation) bool {
+		return r.Metric == metric
+	})
+	if selected == -1 {
+		return errors.Newf(ctx, "no recommendation for the metric %s of the %s containers", metric, opts.ContainerType)
+	}
+
+	io.Statusf("Load of the %s containers over the last %v (%d points):\n", opts.ContainerType, opts.Since, len(points))
+	cpu, rpm := make([]float64, len(points)), make([]float64, len(points))
+	for i, point := range points {
+		cpu[i], rpm[i] = point.CPU, point.RPM
+	}
+	io.Infof("CPU per container: median %.2f, p95 %.2f, max %.2f\n", percentile(cpu, 0.5), percentile(cpu, 0.95), slices.Max(cpu))
+	if opts.ContainerType == "web" {
+		io.Infof("RPM per container: median %.0f, p95 %.0f, max %.0f\n", percentile(rpm, 0.5), percentile(rpm, 0.95), slices.Max(rpm))
+	}
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Configuration", "Metric", "Target", "Min", "Max", "Avg Containers", "Peak", "Saturated"})
+	appendReplay := func(name string, r recommendation) {
+		result := replay(points, r)
+		target := "-"
+		if r.Metric != "" {
+			target = formatTarget(r)
+		}
+		_ = t.Append([]string{
+			name, r.Metric, target, strconv.Itoa(r.MinContainers), strconv.Itoa(r.MaxContainers),
+			fmt.Sprintf("%.1f", result.AverageContainers), strconv.Itoa(result.PeakContainers),
+			fmt.Sprintf("%.1f%%", result.SaturatedRatio*100),
+		})
+	}
+	appendReplay("Fixed formation", recommendation{MinContainers: currentAmount, MaxContainers: currentAmount})
+	if hasAutoscaler && slices.Contains(RecommendMetrics, current.Metric) {
+		appendReplay("Current autoscaler", recommendation{
+			Metric: current.Metric, Target: current.Target, MinContainers: current.MinContainers, MaxContainers: current.MaxContainers,
+		})
+	}
+	for i, r := range recommendations {
+		name := "Recommended"
+		if i == selected {
+			name += " (*)"
+		}
+		appendReplay(name, r)
+	}
+	_ = t.Render()
+
+	r := recommendations[selected]
+	if !opts.Apply {
+		command := "autoscalers-add"
+		if hasAutoscaler {
+			command = "autoscalers-update"
+		}
+		io.Infof(
+			"Apply the recommendation (*) with --apply, or with:\nscalingo --app %s %s --container-type %s --metric %s --target %s --min-containers %d --max-containers %d\n",
+			app, command, opts.ContainerType, r.Metric, formatTarget(r), r.MinContainers, r.MaxContainers,
+		)
+		return nil
+	}
+
+	if hasAutoscaler {
+		_, err = c.AutoscalerUpdate(ctx, app, current.ID, scalingo.AutoscalerUpdateParams{
+			Metric:        &r.Metric,
+			Target:        &r.Target,
+			MinContainers: &r.MinContainers,
+			MaxContainers: &r.MaxContainers,
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "update autoscaler %s on app %s", current.ID, app)
+		}
+		io.Status("Autoscaler updated on", app, "for", opts.ContainerType, "containers")
+		return nil
+	}
+	_, err = c.AutoscalerAdd(ctx, app, scalingo.AutoscalerAddParams{
+		ContainerType: opts.ContainerType,
+		Metric:        r.Metric,
+		Target:        r.Target,
+		MinContainers: r.MinContainers,
+		MaxContainers: r.MaxContainers,
+	})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create autoscaler on app %s", app)
+	}
+	io.Status("Autoscaler created on", app, "for", opts.ContainerType, "containers")
+	return nil
+}
+
+// fetchLoad returns the load of the container type at each point of its CPU
+// metric. The amount of web containers and their RPM come from the router
+// metrics, the other container types are considered at their current
+// amount.
+func fetchLoad(ctx context.Context, c *scalingo.Client, app string, opts RecommendOpts, currentAmount int) ([]loadPoint, error) {
+	now := time.Now()
+	cpu, err := metrics.Fetch(ctx, c, app, metrics.Query{Metric: scalingo.MetricCPU, Since: opts.Since, ContainerType: opts.ContainerType}, now)
+	if err != nil {
+		return nil, err
+	}
+	var amounts, rpm []metrics.Point
+	if opts.ContainerType == "web" {
+		amounts, err = metrics.Fetch(ctx, c, app, metrics.Query{Metric: scalingo.MetricRouterServersAmount, Since: opts.Since}, now)
+		if err != nil {
+			return nil, err
+		}
+		rpm, err = metrics.Fetch(ctx, c, app, metrics.Query{Metric: scalingo.MetricRouterRPMPerContainer, Since: opts.Since}, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	points := make([]loadPoint, 0, len(cpu))
+	for _, point := range cpu {
+		containers := int(math.Round(valueAt(amounts, point.Time, float64(currentAmount))))
+		points = append(points, loadPoint{
+			Time:       point.Time,
+			Containers: max(containers, 1),
+			CPU:        point.Value,
+			RPM:        valueAt(rpm, point.Time, 0),
+		})
+	}
+	return points, nil
+}
+
+// valueAt returns the value of the last point at or before t, the default
+// value if there is none. The points are ordered by time.
+func valueAt(points []metrics.Point, t time.Time, defaultValue float64) float64 {
+	i, found := slices.BinarySearchFunc(points, t, func(point metrics.Point, t time.Time) int {
+		return point.Time.Compare(t)
+	})
+	if found {
+		return points[i].Value
+	}
+	if i == 0 {
+		return defaultValue
+	}
+	return points[i-1].Value
+}
+
+// recommend returns the recommended configurations for the CPU metric, and
+// for the RPM per container metric if there are requests. The RPM target is
+// the RPM per container handled at the CPU target.
+func recommend(points []loadPoint) []recommendation {
+	recommendations := []recommendation{recommendFor(points, scalingo.MetricCPU, defaultCPUTarget)}
+
+	var ratios []float64
+	for _, point := range points {
+		if point.CPU > idleCPU && point.RPM > 0 {
+			ratios = append(ratios, point.RPM/point.CPU)
+		}
+	}
+	if len(ratios) > 0 {
+		target := math.Round(percentile(ratios, 0.5) * defaultCPUTarget)
+		if target >= 1 {
+			recommendations = append(recommendations, recommendFor(points, scalingo.MetricRouterRPMPerContainer, target))
+		}
+	}
+	return recommendations
+}
+
+// recommendFor returns the minimal and maximal amounts of containers needed
+// to keep the metric at the target over the period
+func recommendFor(points []loadPoint, metric string, target float64) recommendation {
+	needed := make([]float64, len(points))
+	for i, point := range points {
+		needed[i] = float64(neededContainers(point, metric, target))
+	}
+	minContainers := max(int(percentile(needed, minContainersPercentile)), 1)
+	maxContainers := max(int(math.Ceil(slices.Max(needed)*maxContainersHeadroom)), minContainers)
+	return recommendation{Metric: metric, Target: target, MinContainers: minContainers, MaxContainers: maxContainers}
+}
+
+// neededContainers returns the amount of containers keeping the metric at
+// the target for the total load of the point
+func neededContainers(point loadPoint, metric string, target float64) int {
+	value := point.CPU
+	if metric == scalingo.MetricRouterRPMPerContainer {
+		value = point.RPM
+	}
+	return max(int(math.Ceil(value*float64(point.Containers)/target)), 1)
+}
+
+// replay simulates the amount of containers which would have run over the
+// period with the configuration. Without metric, the amount is fixed.
+func replay(points []loadPoint, r recommendation) replayResult {
+	var result replayResult
+	if len(points) == 0 {
+		return result
+	}
+	total, saturated := 0, 0
+	for _, point := range points {
+		containers := r.MinContainers
+		if r.Metric != "" {
+			needed := neededContainers(point, r.Metric, r.Target)
+			if needed > r.MaxContainers {
+				saturated++
+			}
+			containers = min(max(needed, r.MinContainers), r.MaxContainers)
+		} else if neededContainers(point, scalingo.MetricCPU, defaultCPUTarget) > r.MaxContainers {
+			saturated++
+		}
+		total += containers
+		result.PeakContainers = max(result.PeakContainers, containers)
+	}
+	result.AverageContainers = float64(total) / float64(len(points))
+	result.SaturatedRatio = float64(saturated) / float64(len(points))
+	return result
+}
+
+// percentile returns the nearest rank percentile of the values
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(values))
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(rank, 0), len(sorted)-1)]
+}
+
+func formatTarget(r recommendation) string {
+	if r.Metric == scalingo.MetricCPU {
+		return fmt.Sprintf("%.2f", r.Target)
+	}
+	return fmt.Sprintf("%.0f", r.Target)
+}
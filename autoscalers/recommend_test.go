@@ -0,0 +1,55 @@
+package autoscalers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestRecommend(t *testing.T) {
+	// 2 containers at 0.35 of CPU and 100 RPM most of the time, with a peak
+	// at 0.7 of CPU and 200 RPM
+	var points []loadPoint
+	for range 19 {
+		points = append(points, loadPoint{Containers: 2, CPU: 0.35, RPM: 100})
+	}
+	points = append(points, loadPoint{Containers: 2, CPU: 0.7, RPM: 200})
+
+	recommendations := recommend(points)
+
+	assert.Equal(t, []recommendation{
+		{Metric: scalingo.MetricCPU, Target: 0.7, MinContainers: 1, MaxContainers: 3},
+		{Metric: scalingo.MetricRouterRPMPerContainer, Target: 200, MinContainers: 1, MaxContainers: 3},
+	}, recommendations)
+}
+
+func TestReplay(t *testing.T) {
+	points := []loadPoint{
+		{Containers: 2, CPU: 0.35},
+		{Containers: 2, CPU: 0.35},
+		{Containers: 2, CPU: 1.4},
+		{Containers: 2, CPU: 2.8},
+	}
+
+	tests := map[string]struct {
+		recommendation recommendation
+		expectedResult replayResult
+	}{
+		"fixed formation": {
+			recommendation: recommendation{MinContainers: 2, MaxContainers: 2},
+			expectedResult: replayResult{AverageContainers: 2, PeakContainers: 2, SaturatedRatio: 0.5},
+		},
+		"autoscaler": {
+			recommendation: recommendation{Metric: scalingo.MetricCPU, Target: 0.7, MinContainers: 1, MaxContainers: 6},
+			expectedResult: replayResult{AverageContainers: 3, PeakContainers: 6, SaturatedRatio: 0.25},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedResult, replay(points, test.recommendation))
+		})
+	}
+}
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"strings"
 
 	"github.com/urfave/cli/v3"
 
@@ -32,11 +31,8 @@ var (
 			projectSlug := c.String("project")
 			format := renderer.Format(c.String("format"))
 
-			if projectSlug != "" {
-				projectSlugSplit := strings.Split(projectSlug, "/")
-				if len(projectSlugSplit) != 2 || (len(projectSlugSplit) == 2 && (projectSlugSplit[0] == "" || projectSlugSplit[1] == "")) {
-					errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "apps")
-				}
+			if !isValidProjectSlug(projectSlug) {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "apps")
 			}
 
 			var appsRenderer renderer.Renderer[[]*scalingo.App]
@@ -2,14 +2,18 @@ package cmd
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 
 	"github.com/Scalingo/cli/autoscalers"
 	"github.com/Scalingo/cli/cmd/autocomplete"
 	"github.com/Scalingo/cli/detect"
+	"github.com/Scalingo/cli/metrics"
 	"github.com/Scalingo/cli/utils"
 	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 var (
@@ -254,6 +258,64 @@ var (
 			_ = autocomplete.CmdFlagsAutoComplete(c, "autoscalers-remove")
 		},
 	}
+
+	autoscalersRecommendCommand = cli.Command{
+		Name:     "autoscalers-recommend",
+		Category: "Autoscalers",
+		Usage:    "Recommend an autoscaler configuration from the observed load",
+		Flags: []cli.Flag{&appFlag,
+			&cli.StringFlag{Name: "type", Aliases: []string{"container-type", "c"}, Value: "web", Usage: "Container type to analyse"},
+			&cli.StringFlag{Name: "since", Value: "7d", Usage: "Period of the analysed metrics, e.g. 12h or 7d"},
+			&cli.StringFlag{Name: "metric", Aliases: []string{"m"}, Usage: "Metric of the recommendation to apply [" + strings.Join(autoscalers.RecommendMetrics, "|") + "]"},
+			&cli.BoolFlag{Name: "apply", Usage: "Create or update the autoscaler with the recommendation"},
+		},
+		Description: CommandDescription{
+			Description: `Recommend an autoscaler configuration from the observed load
+
+The CPU metric of the containers, and the RPM per container for the web containers, are analysed over the period. A configuration is recommended for each metric, with a replay of the amount of containers which would have run over the period, compared to the current formation and autoscaler. The saturated column is the share of the period during which more containers than the maximum would have been needed.
+
+The recommendation of the '--metric' flag, the metric of the current autoscaler or CPU is applied with '--apply'.`,
+			Examples: []string{
+				"scalingo --app my-app autoscalers-recommend --type web --since 7d",
+				"scalingo --app my-app autoscalers-recommend --type worker --since 3d --apply",
+				"scalingo --app my-app autoscalers-recommend --metric rpm_per_container --apply",
+			},
+			SeeAlso: []string{"autoscalers", "autoscalers-add", "autoscalers-update", "metrics"},
+		}.Render(),
+
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 0 {
+				_ = cli.ShowCommandHelp(ctx, c, "autoscalers-recommend")
+				return nil
+			}
+			since, err := metrics.ParseSince(ctx, c.String("since"))
+			if err != nil {
+				errorQuitWithHelpMessage(ctx, err, c, "autoscalers-recommend")
+			}
+			metric := c.String("metric")
+			if metric != "" && !slices.Contains(autoscalers.RecommendMetrics, metric) {
+				errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid metric '%s'", metric), c, "autoscalers-recommend")
+			}
+
+			currentApp := detect.CurrentApp(ctx, c)
+
+			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
+
+			err = autoscalers.Recommend(ctx, currentApp, autoscalers.RecommendOpts{
+				ContainerType: c.String("type"),
+				Since:         since,
+				Metric:        metric,
+				Apply:         c.Bool("apply"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "autoscalers-recommend")
+		},
+	}
 )
 
 func isValidAutoscalerAddOpts(c *cli.Command) bool {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/Scalingo/cli/detect"
 	"github.com/Scalingo/cli/io"
 	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 var (
@@ -76,11 +78,24 @@ var (
 			Name:    "silent",
 			Aliases: []string{"s"},
 			Usage:   "Do not show progress bar and loading messages",
+		}, &cli.IntFlag{
+			Name:  "parallel",
+			Value: 1,
+			Usage: "Number of chunks of the backup downloaded simultaneously",
+		}, &cli.BoolFlag{
+			Name:  "verify-only",
+			Usage: "Verify the already downloaded archive of the backup and list its files",
 		}},
 		Description: CommandDescription{
-			Description: "Download a specific backup",
-			Examples:    []string{"scalingo --app my-app --addon addon_uuid backups-download --backup my_backup"},
-			SeeAlso:     []string{"backups", "addons"},
+			Description: `Download a specific backup. Interrupted downloads are resumed when the command is run again with the same output. Large backups can be downloaded faster with '--parallel', by chunks downloaded simultaneously.
+
+Once downloaded, the size of the archive and its gzip and tar structures are verified. '--verify-only' only verifies an already downloaded archive and lists the files it contains.`,
+			Examples: []string{
+				"scalingo --app my-app --addon addon_uuid backups-download --backup my_backup",
+				"scalingo --app my-app --addon addon_uuid backups-download --backup my_backup --parallel 4",
+				"scalingo --app my-app --addon addon_uuid backups-download --backup my_backup --output backup.tar.gz --verify-only",
+			},
+			SeeAlso: []string{"backups", "addons"},
 		}.Render(),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			currentApp := detect.CurrentApp(ctx, c)
@@ -90,8 +105,10 @@ var (
 
 			backup := c.String("backup")
 			opts := db.DownloadBackupOpts{
-				Output: c.String("output"),
-				Silent: c.Bool("silent"),
+				Output:     c.String("output"),
+				Silent:     c.Bool("silent"),
+				Parallel:   c.Int("parallel"),
+				VerifyOnly: c.Bool("verify-only"),
 			}
 
 			err := db.DownloadBackup(ctx, currentApp, addonName, backup, opts)
@@ -102,6 +119,162 @@ var (
 		},
 	}
 
+	backupsRestoreCommand = cli.Command{
+		Name:     "backups-restore",
+		Category: "Addons",
+		Usage:    "Restore a backup into a database",
+		Flags: []cli.Flag{&appFlag, &addonFlag,
+			&cli.StringFlag{Name: "backup", Aliases: []string{"b"}, Usage: "ID of the backup to restore", DefaultText: "last successful backup"},
+			&cli.StringFlag{Name: "file", Aliases: []string{"f"}, Usage: "Local backup archive to restore instead of a backup of the addon"},
+			&cli.StringFlag{Name: "target-app", Usage: "App whose database is restored", DefaultText: "current app"},
+			&cli.StringFlag{Name: "confirm", Usage: "Name of the target app, to skip the interactive confirmation"},
+			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the one-off container"},
+		},
+		Description: CommandDescription{
+			Description: `Restore a backup of a PostgreSQL, MySQL, MongoDB or Redis addon into the database of the same type of the target app (the current app by default).
+
+The restoration is done from a one-off container of the target app. The size and the integrity of the archive are verified before the database is restored. A local archive (e.g. downloaded with 'backups-download') can be restored with '--file', its SHA256 checksum is computed locally and verified once uploaded. Uploaded archives are limited to 100 MiB.
+
+Redis backups can only be restored if the archive contains an AOF file.
+
+As all the data of the target database are replaced, the name of the target app must always be typed to confirm, or given with '--confirm'.`,
+			Examples: []string{
+				"scalingo --app my-app --addon postgresql backups-restore --target-app my-app-staging",
+				"scalingo --app my-app-staging --addon postgresql backups-restore --file backup.tar.gz",
+				"scalingo --app my-app --addon mysql backups-restore --backup 5bb95a904ffb096e9a2831b8 --confirm my-app",
+			},
+			SeeAlso: []string{"backups", "backups-download", "db-clone"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			currentApp := detect.CurrentApp(ctx, c)
+			addonName := addonUUIDFromFlags(ctx, c, currentApp, true)
+
+			targetApp := c.String("target-app")
+			if targetApp == "" {
+				targetApp = currentApp
+			}
+			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeDBs)
+			utils.CheckForConsent(ctx, targetApp, utils.ConsentTypeDBs, utils.ConsentTypeContainers)
+
+			err := db.RestoreBackup(ctx, db.RestoreBackupOpts{
+				App:       currentApp,
+				AddonID:   addonName,
+				BackupID:  c.String("backup"),
+				File:      c.String("file"),
+				TargetApp: targetApp,
+				Confirm:   c.String("confirm"),
+				Size:      c.String("size"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
+	backupsReportCommand = cli.Command{
+		Name:     "backups-report",
+		Category: "Addons",
+		Usage:    "Check the backups of all your databases",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Usage: "Only consider the apps of a project. The filter uses the format <ownerUsername>/<projectName>"},
+			&cli.DurationFlag{Name: "max-age", Value: 26 * time.Hour, Usage: "Maximal age of the last successful backup of a database"},
+			&cli.DurationFlag{Name: "failures-period", Value: 7 * 24 * time.Hour, Usage: "Period during which failed backups are reported"},
+			&cli.StringFlag{Name: "format", Value: outputFormatTable, Usage: "[" + outputFormatJSON + "|" + outputFormatTable + "]"},
+		},
+		Description: CommandDescription{
+			Description: `Check the backups of the database addons of all your apps. A database is reported if:
+
+- its last successful backup is older than '--max-age' (or it has no successful backup),
+- backups failed during the '--failures-period',
+- its periodic backups are disabled or have no valid schedule.
+
+The command exits with a non-zero status if a database is reported, so that it can be
+used as a periodic check, e.g. in a nightly cron job with '--format json'.`,
+			Examples: []string{
+				"scalingo backups-report",
+				"scalingo backups-report --project my-user/my-project --max-age 48h",
+				"scalingo backups-report --format json",
+			},
+			SeeAlso: []string{"backups", "backups-config"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			projectSlug := c.String("project")
+			if !isValidProjectSlug(projectSlug) {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "backups-report")
+			}
+			format := c.String("format")
+			if format != outputFormatJSON && format != outputFormatTable {
+				errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid format '%s'", format), c, "backups-report")
+			}
+
+			err := db.BackupsReport(ctx, db.BackupsReportOpts{
+				ProjectSlug:    projectSlug,
+				MaxAge:         c.Duration("max-age"),
+				FailuresPeriod: c.Duration("failures-period"),
+				JSON:           format == outputFormatJSON,
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
+	backupsSyncCommand = cli.Command{
+		Name:     "backups-sync",
+		Category: "Addons",
+		Usage:    "Copy the backups of databases to a local directory or an S3 bucket",
+		Flags: []cli.Flag{&appFlag, &addonFlag,
+			&cli.StringFlag{Name: "dest", Usage: "Destination of the backups: a local directory or s3://bucket/prefix", Required: true},
+			&cli.IntFlag{Name: "retain", Usage: "Number of backups kept per database, older backups are deleted from the destination", DefaultText: "all"},
+			&cli.BoolFlag{Name: "all", Usage: "Sync the backups of the databases of all your apps"},
+			&cli.StringFlag{Name: "project", Usage: "With --all, only consider the apps of a project. The filter uses the format <ownerUsername>/<projectName>"},
+			&cli.IntFlag{Name: "parallel", Value: 1, Usage: "Number of chunks of a backup downloaded simultaneously"},
+		},
+		Description: CommandDescription{
+			Description: `Copy the successful backups of a database addon, or of all your database addons with '--all', which are not yet in the destination. The backups of an addon are stored in the '<app>/<addon ID>' directory of the destination, in archives named after their creation date and their ID. Once copied, the backups older than the '--retain' most recent ones are deleted from the destination. A 'manifest.json' file at the root of the destination describes the copied backups.
+
+The backups are downloaded and verified like with 'backups-download': interrupted downloads are resumed on the next synchronization.
+
+The destination can be a bucket of any S3-compatible object storage. The credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables, the endpoint from AWS_ENDPOINT_URL_S3 (AWS S3 by default) and the region from AWS_REGION (us-east-1 by default).
+
+The command exits with a non-zero status if the backups of a database failed to sync, so that it can be used in a nightly cron job.`,
+			Examples: []string{
+				"scalingo --app my-app --addon postgresql backups-sync --dest ./backups/ --retain 14",
+				"scalingo backups-sync --all --dest ./backups/ --retain 14",
+				"AWS_ENDPOINT_URL_S3=https://s3.fr-par.scw.cloud AWS_REGION=fr-par scalingo backups-sync --all --project my-user/my-project --dest s3://my-bucket/scalingo --retain 30",
+			},
+			SeeAlso: []string{"backups", "backups-download", "backups-report"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Int("retain") < 0 {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "the number of retained backups must be positive"), c, "backups-sync")
+			}
+			opts := db.BackupsSyncOpts{
+				All:         c.Bool("all"),
+				ProjectSlug: c.String("project"),
+				Dest:        c.String("dest"),
+				Retain:      c.Int("retain"),
+				Parallel:    c.Int("parallel"),
+			}
+			if !isValidProjectSlug(opts.ProjectSlug) {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "backups-sync")
+			}
+			if !opts.All {
+				opts.App = detect.CurrentApp(ctx, c)
+				opts.AddonID = addonUUIDFromFlags(ctx, c, opts.App, true)
+				utils.CheckForConsent(ctx, opts.App, utils.ConsentTypeDBs)
+			}
+
+			err := db.BackupsSync(ctx, opts)
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
 	backupDownloadCommand = cli.Command{
 		Name:        "backup-download",
 		Category:    backupsDownloadCommand.Category,
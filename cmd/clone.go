@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/cli/cmd/autocomplete"
+	"github.com/Scalingo/cli/utils"
+)
+
+var (
+	cloneCommand = cli.Command{
+		Name:     "clone",
+		Category: "Global",
+		Usage:    "Create a new app from an existing one",
+		Description: CommandDescription{
+			Description: `Create a new app with the stack, project and region of an existing app, and copy its configuration
+
+The routing settings, formation, autoscalers, notifiers, alerts and collaborators are copied. The collaborators are invited to the new app. An app can only be scaled once deployed: if the formation can't be copied, the scale command to run after the first deployment is reported.
+
+With '--with-addons', the addons are provisioned with the same plans, without their data. With '--with-env', the environment variables are copied, except the ones generated by the addons. The aliases to variables generated by addons which are not provisioned on the new app are not copied either. With '--with-domains-suffix', the domains are copied with the suffix appended to their first label, e.g. 'www.example.com' becomes 'www-staging.example.com' with '-staging'. The certificates are not copied.
+
+The new app is created in the region of the existing app: the command fails if '--region' designates another region.
+
+A report of what was copied and what was skipped is displayed at the end.`,
+			Examples: []string{
+				"scalingo clone --from my-app --to my-app-staging",
+				"scalingo clone --from my-app --to my-app-tenant-b --with-env --with-addons",
+				"scalingo clone --from my-app --to my-app-staging --with-domains-suffix=-staging",
+			},
+			SeeAlso: []string{"create", "scale", "env", "addons"},
+		}.Render(),
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "from", Usage: "Application to clone", Required: true},
+			&cli.StringFlag{Name: "to", Usage: "Name of the new application", Required: true},
+			&cli.BoolFlag{Name: "with-env", Usage: "Copy the environment variables, except the ones generated by the addons"},
+			&cli.BoolFlag{Name: "with-addons", Usage: "Provision the same addons with the same plans, without their data"},
+			&cli.StringFlag{Name: "with-domains-suffix", Usage: "Copy the domains with this suffix appended to their first label"},
+		},
+
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() != 0 {
+				_ = cli.ShowCommandHelp(ctx, c, "clone")
+				return nil
+			}
+			utils.CheckForConsent(ctx, c.String("from"), utils.ConsentTypeContainers)
+
+			err := apps.Clone(ctx, apps.CloneOpts{
+				From:          c.String("from"),
+				To:            c.String("to"),
+				WithEnv:       c.Bool("with-env"),
+				WithAddons:    c.Bool("with-addons"),
+				DomainsSuffix: c.String("with-domains-suffix"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "clone")
+		},
+	}
+)
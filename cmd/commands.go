@@ -157,6 +157,7 @@ var (
 		// Apps
 		&appsCommand,
 		&CreateCommand,
+		&cloneCommand,
 		&DestroyCommand,
 		&renameCommand,
 		&appsInfoCommand,
@@ -174,8 +175,11 @@ var (
 		// Apps Process Actions
 		&psCommand,
 		&scaleCommand,
+		&containerSizesCommand,
+		&costCommand,
 		&RestartCommand,
 		&sendSignalCommand,
+		&killCommand,
 
 		// Routing Settings
 		&forceHTTPSCommand,
@@ -260,14 +264,18 @@ var (
 		&databaseBackupsConfig,
 		&databaseEnableFeature,
 		&databaseDisableFeature,
+		&databaseVersionsCommand,
 		&databaseListUsers,
 		&databaseDeleteUser,
 		&databaseCreateUser,
 		&databaseUpdateUserPassword,
+		&databaseUsersReap,
+		&databaseCredentialsRotate,
 
 		// Maintenance
 		&databaseMaintenanceList,
 		&databaseMaintenanceInfo,
+		&maintenanceCalendar,
 
 		// PITR
 		&databasePITRRestore,
@@ -278,6 +286,10 @@ var (
 		&backupsCreateCommand,
 		&backupsDownloadCommand,
 		&backupDownloadCommand,
+		&backupsRestoreCommand,
+		&backupsReportCommand,
+		&backupsSyncCommand,
+		&dbCloneCommand,
 
 		// Alerts
 		&alertsListCommand,
@@ -289,11 +301,14 @@ var (
 
 		// Stats
 		&StatsCommand,
+		&metricsCommand,
+		&topCommand,
 
 		// Autoscalers
 		&autoscalersListCommand,
 		&autoscalersAddCommand,
 		&autoscalersRemoveCommand,
+		&autoscalersRecommendCommand,
 		&autoscalersUpdateCommand,
 		&autoscalersDisableCommand,
 		&autoscalersEnableCommand,
@@ -318,6 +333,8 @@ var (
 
 		// Private Networks
 		&privateNetworksApplicationDomainsListCommand,
+		&portForwardCommand,
+		&socksProxyCommand,
 	}
 
 	regionalPreviewCommands = []*cli.Command{
@@ -333,6 +350,7 @@ var (
 		&databaseFirewallRulesListCommand,
 		&databaseFirewallRulesAddCommand,
 		&databaseFirewallRulesRemoveCommand,
+		&databaseFirewallRulesSyncCommand,
 		&databaseFirewallManagedRangesCommand,
 
 		// Network
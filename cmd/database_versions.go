@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/cmd/autocomplete"
+	"github.com/Scalingo/cli/db"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+var databaseVersionsCommand = cli.Command{
+	Name:     "database-versions",
+	Category: "Addons",
+	Usage:    "List the versions of all your databases and their upgrade path",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "project", Usage: "Only consider the apps of a project. The filter uses the format <ownerUsername>/<projectName>"},
+	},
+	Description: CommandDescription{
+		Description: `List the database addons of all your apps with their version, their enabled plugins,
+the path to upgrade them to the latest version available and the end of life of
+their version. The databases whose version reached or is close to its end of life
+are displayed first.
+
+The end of life dates are advisory: they are the ones announced by the PostgreSQL,
+MySQL and MongoDB projects when this version of the CLI was released, and are
+unknown for the other databases and the versions announced since.`,
+		Examples: []string{
+			"scalingo database-versions",
+			"scalingo database-versions --project my-user/my-project",
+		},
+		SeeAlso: []string{"addons-info", "maintenance-calendar"},
+	}.Render(),
+
+	Action: func(ctx context.Context, c *cli.Command) error {
+		projectSlug := c.String("project")
+		if !isValidProjectSlug(projectSlug) {
+			errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "database-versions")
+		}
+
+		err := db.ListVersions(ctx, projectSlug)
+		if err != nil {
+			errorQuit(ctx, err)
+		}
+		return nil
+	},
+	ShellComplete: func(_ context.Context, c *cli.Command) {
+		_ = autocomplete.CmdFlagsAutoComplete(c, "database-versions")
+	},
+}
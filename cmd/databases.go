@@ -224,16 +224,21 @@ Only available on ` + fmt.Sprintf("%s", dbUsers.SupportedAddons),
 			&appFlag,
 			&addonFlag,
 			databaseFlag(),
-			&cli.BoolFlag{Name: "read-only", Usage: "Create a user with read-only rights"},
+			&cli.BoolFlag{Name: "read-only", Aliases: []string{"readonly"}, Usage: "Create a user with read-only rights"},
+			&cli.DurationFlag{Name: "ttl", Usage: "Time to live of a temporary user, deleted by database-users-reap once expired (e.g. 8h)"},
 		},
 		Description: CommandDescription{
 			Description: `Create new database user
 
+With '--ttl', a temporary user is created with a generated password, and its connection URL is displayed. Its expiry is recorded in the configuration directory of the CLI: 'database-users-reap' deletes it once expired.
+
 Only available on ` + fmt.Sprintf("%s", dbUsers.SupportedAddons),
 			Examples: []string{
 				"scalingo --app myapp --addon addon-uuid database-users-create my_user",
 				"scalingo --app myapp --addon addon-uuid database-users-create --read-only my_user",
+				"scalingo --app myapp --addon addon-uuid database-users-create --read-only --ttl 8h analyst",
 			},
+			SeeAlso: []string{"database-users-reap"},
 		}.Render(),
 
 		Action: func(ctx context.Context, c *cli.Command) error {
@@ -248,13 +253,95 @@ Only available on ` + fmt.Sprintf("%s", dbUsers.SupportedAddons),
 
 			username := c.Args().First()
 
-			err := dbUsers.CreateUser(ctx, currentResource, addonName, username, c.Bool("read-only"))
+			if c.Duration("ttl") < 0 {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "the time to live must be positive"), c, "database-users-create")
+			}
+
+			err := dbUsers.CreateUser(ctx, currentResource, addonName, username, dbUsers.CreateUserOpts{
+				ReadOnly: c.Bool("read-only"),
+				TTL:      c.Duration("ttl"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
+	databaseUsersReap = cli.Command{
+		Name:     "database-users-reap",
+		Category: "Addons",
+		Usage:    "Delete the expired temporary database users",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{Name: "dry-run", Usage: "Only display the expired users"},
+		},
+		Description: CommandDescription{
+			Description: `Delete the temporary database users created with 'database-users-create --ttl' which are expired, on all the databases.
+
+The expiry of the temporary users is recorded by the CLI which created them: the command must run on the same machine, with the same user. It can run periodically from cron, it exits with a non-zero status if an expired user could not be deleted. Without a terminal, the consent of the operators is not asked: the users of the apps without consent are not deleted.`,
+			Examples: []string{
+				"scalingo database-users-reap",
+				"scalingo database-users-reap --dry-run",
+			},
+			SeeAlso: []string{"database-users-create", "database-users-list"},
+		}.Render(),
+
+		Action: func(ctx context.Context, c *cli.Command) error {
+			err := dbUsers.Reap(ctx, dbUsers.ReapOpts{DryRun: c.Bool("dry-run")})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
+	databaseCredentialsRotate = cli.Command{
+		Name:     "database-credentials-rotate",
+		Category: "Addons",
+		Usage:    "Rotate the password of a database user and update the apps using it",
+		Flags: []cli.Flag{
+			&appFlag,
+			&addonFlag,
+			databaseFlag(),
+			&cli.StringFlag{Name: "user", Usage: "Database user whose password is rotated", Required: true},
+			&cli.BoolFlag{Name: "restart", Usage: "Restart the apps whose environment is updated, container by container"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Do not ask for confirmation"},
+		},
+		Description: CommandDescription{
+			Description: `Reset the password of an unprotected database user, then update the environment variables containing its credentials.
+
+All your apps are searched for environment variables whose value is a connection URL to the database with the credentials of the user (e.g. a 'REPORTING_DATABASE_URL' derived from the 'SCALINGO_*_URL' variable of the database): the variables of the app of the database and of the linked apps are updated with the new password. Aliases to other variables are left as is.
+
+With '--restart', the updated apps are restarted to use the new credentials with a rolling restart: their containers are restarted one by one, each container being running before the next one is restarted. Finally, the new credentials are verified by connecting to the database from a one-off container of the app of the database.
+
+Only available on PostgreSQL, MySQL and MongoDB`,
+			Examples: []string{
+				"scalingo --app myapp --addon addon-uuid database-credentials-rotate --user app_user",
+				"scalingo --app myapp --addon addon-uuid database-credentials-rotate --user app_user --restart --yes",
+			},
+			SeeAlso: []string{"database-users-list", "database-users-update-password", "env"},
+		}.Render(),
+
+		Action: func(ctx context.Context, c *cli.Command) error {
+			currentResource := detect.GetCurrentResource(ctx, c)
+			utils.CheckForConsent(ctx, currentResource, utils.ConsentTypeDBs)
+
+			addonName := addonUUIDFromFlags(ctx, c, currentResource, true)
+
+			err := db.RotateCredentials(ctx, db.RotateCredentialsOpts{
+				App:      currentResource,
+				AddonID:  addonName,
+				Username: c.String("user"),
+				Restart:  c.Bool("restart"),
+				Yes:      c.Bool("yes"),
+			})
 			if err != nil {
 				errorQuit(ctx, err)
 			}
 			return nil
 		},
 	}
+
 	databaseUpdateUserPassword = cli.Command{
 		Name:      "database-users-update-password",
 		Aliases:   []string{"database-update-user-password"},
@@ -273,6 +360,7 @@ Only available on ` + fmt.Sprintf("%s", dbUsers.SupportedAddons),
 			Examples: []string{
 				"scalingo --app myapp --addon addon-uuid database-users-update-password my_user",
 			},
+			SeeAlso: []string{"database-credentials-rotate"},
 		}.Render(),
 
 		Action: func(ctx context.Context, c *cli.Command) error {
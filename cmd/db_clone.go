@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/cmd/autocomplete"
+	"github.com/Scalingo/cli/db"
+	"github.com/Scalingo/cli/utils"
+)
+
+var (
+	dbCloneCommand = cli.Command{
+		Name:     "db-clone",
+		Category: "Addons",
+		Usage:    "Copy the data of a database into the database of another app",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "from-app", Usage: "App of the database to copy", Required: true},
+			&cli.StringFlag{Name: "to-app", Usage: "App of the database to overwrite", Required: true},
+			&cli.StringFlag{Name: "addon", Usage: "Type (postgresql, mysql, mongodb) or ID of the addon of the source app", Required: true},
+			&cli.BoolFlag{Name: "live", Usage: "Dump the source database instead of restoring its last backup"},
+			&cli.StringFlag{Name: "backup", Aliases: []string{"b"}, Usage: "ID of the backup to restore", DefaultText: "last successful backup"},
+			&cli.StringSliceFlag{Name: "exclude-table", Usage: "Table (or collection) whose data must not be copied (can be repeated)"},
+			&cli.StringFlag{Name: "anonymize-script", Usage: "Local script executed on the target database after the clone"},
+			&cli.StringFlag{Name: "confirm", Usage: "Name of the target app, to skip the interactive confirmation"},
+			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the one-off container"},
+		},
+		Description: CommandDescription{
+			Description: `Replace the data of the database of the target app with the data of the database of the source app, e.g. to refresh a staging app from production.
+
+A one-off container is started on the target app. By default it restores the last successful backup of the source database. The '--live' flag dumps the source database instead ('pg_dump', 'mysqldump' or 'mongodump'). PostgreSQL, MySQL and MongoDB addons are supported.
+
+The data of the tables given with '--exclude-table' are not copied (MySQL tables are completely excluded, which is only possible with '--live').
+
+Once the data are copied, the script given with '--anonymize-script' is uploaded and executed on the target database ('psql', 'mysql' or 'mongo').
+
+As all the data of the target database are replaced, the name of the target app must be typed to confirm, or given with '--confirm'.`,
+			Examples: []string{
+				"scalingo db-clone --from-app my-app --to-app my-app-staging --addon postgresql",
+				"scalingo db-clone --from-app my-app --to-app my-app-staging --addon postgresql --live --exclude-table audit_logs --anonymize-script anonymize.sql",
+				"scalingo db-clone --from-app my-app --to-app my-app-staging --addon mongodb --confirm my-app-staging",
+			},
+			SeeAlso: []string{"backups", "run"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() > 0 {
+				_ = cli.ShowCommandHelp(ctx, c, "db-clone")
+				return nil
+			}
+
+			utils.CheckForConsent(ctx, c.String("from-app"), utils.ConsentTypeDBs)
+			utils.CheckForConsent(ctx, c.String("to-app"), utils.ConsentTypeDBs, utils.ConsentTypeContainers)
+
+			err := db.Clone(ctx, db.CloneOpts{
+				FromApp:         c.String("from-app"),
+				ToApp:           c.String("to-app"),
+				Addon:           c.String("addon"),
+				Live:            c.Bool("live"),
+				BackupID:        c.String("backup"),
+				ExcludeTables:   c.StringSlice("exclude-table"),
+				AnonymizeScript: c.String("anonymize-script"),
+				Confirm:         c.String("confirm"),
+				Size:            c.String("size"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "db-clone")
+		},
+	}
+)
@@ -3,6 +3,8 @@ package cmd
 import (
 	"context"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -25,7 +27,11 @@ var (
 			&cli.IntFlag{Name: "port", Aliases: []string{"p"}, Usage: "Local port to bind (default 10000)"},
 			&cli.StringFlag{Name: "identity", Aliases: []string{"i"}, Usage: "SSH Private Key"},
 			&cli.StringFlag{Name: "bind", Aliases: []string{"b"}, Usage: "IP to bind (default 127.0.0.1)"},
+			&cli.StringFlag{Name: "socket", Aliases: []string{"s"}, Usage: "Path of a Unix domain socket to bind instead of a TCP port"},
 			&cli.BoolFlag{Name: "reconnect", Value: true, Usage: "true by default, automatically reconnect to the tunnel when disconnected"},
+			&cli.DurationFlag{Name: "keepalive-interval", Value: 30 * time.Second, Usage: "Interval between two SSH keepalives, 0 to disable them"},
+			&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "YAML file describing the tunnels to build"},
+			&cli.StringSliceFlag{Name: "var", Usage: "Tunnel to build, format is '[app/]VARIABLE[:port]' (can be repeated)"},
 		},
 		Description: `Create an SSH-encrypted connection to access your Scalingo database locally.
 
@@ -58,17 +64,42 @@ var (
    you want to use to authenticate thanks to the '-i' flag.
 
    Example
-     $ scalingo --app rails-app db-tunnel -i ~/.ssh/custom_key DATABASE_URL`,
+     $ scalingo --app rails-app db-tunnel -i ~/.ssh/custom_key DATABASE_URL
+
+   Multiple tunnels can be multiplexed over a single SSH connection, either by
+   repeating the '--var' flag or with a YAML configuration file. Each tunnel is
+   written '[app/]VARIABLE[:port]', the app defaults to the current one and the
+   port to the next available port after 10000.
+
+   Example
+     $ scalingo --app my-app db-tunnel --var SCALINGO_POSTGRESQL_URL --var other-app/SCALINGO_REDIS_URL:20000
+
+     $ cat tunnels.yml
+     tunnels:
+       - variable: SCALINGO_POSTGRESQL_URL
+         port: 10000
+       - app: other-app
+         variable: SCALINGO_REDIS_URL
+         bind: 0.0.0.0
+     $ scalingo --app my-app db-tunnel --config tunnels.yml
+
+   Instead of a TCP port, the tunnel can be bound to a Unix domain socket with
+   the '--socket' flag (or the 'socket' key of the configuration file) so that
+   local tools can connect without using a TCP port.
+
+   Example
+     $ scalingo --app my-app db-tunnel --socket /tmp/.s.PGSQL.5432 SCALINGO_POSTGRESQL_URL
+     $ psql -h /tmp -U <user> my-app-1
+
+   The SSH connection is kept alive with keepalives sent every 30 seconds, which
+   can be changed with '--keepalive-interval'. If the connection drops, it is
+   transparently rebuilt unless '--reconnect=false' is given. The state of the
+   SSH connection is reported on stderr.
+
+   When the command is interrupted, the number of connections and the amount
+   of data transferred through each tunnel are displayed.`,
 		Action: func(ctx context.Context, c *cli.Command) error {
 			currentResource := detect.GetCurrentResource(ctx, c)
-			isDB, err := utils.IsResourceDatabase(ctx, currentResource)
-			if err != nil && !errors.Is(err, utils.ErrResourceNotFound) {
-				errorQuit(ctx, err)
-			}
-			if isDB {
-				io.Error("It is currently impossible to use `db-tunnel` on a database.")
-				return nil
-			}
 
 			var sshIdentity string
 			if c.String("identity") == "" && os.Getenv("SSH_AUTH_SOCK") != "" {
@@ -78,12 +109,41 @@ var (
 			} else {
 				sshIdentity = c.String("identity")
 			}
-			if c.Args().Len() != 1 {
+
+			var err error
+			var targets []db.TunnelTarget
+			if c.String("config") != "" {
+				targets, err = db.ReadTunnelTargets(ctx, c.String("config"))
+				if err != nil {
+					errorQuit(ctx, err)
+				}
+			}
+			for _, v := range c.StringSlice("var") {
+				target, err := db.ParseTunnelTarget(ctx, v)
+				if err != nil {
+					errorQuit(ctx, err)
+				}
+				targets = append(targets, target)
+			}
+
+			if (len(targets) == 0 && c.Args().Len() != 1) || (len(targets) > 0 && c.Args().Len() != 0) {
 				_ = cli.ShowCommandHelp(ctx, c, "db-tunnel")
 				return nil
 			}
 
-			utils.CheckForConsent(ctx, currentResource, utils.ConsentTypeDBs)
+			// The targets can be databases of other apps, each of them must be
+			// allowed
+			for _, app := range tunnelApps(currentResource, targets) {
+				isDB, err := utils.IsResourceDatabase(ctx, app)
+				if err != nil && !errors.Is(err, utils.ErrResourceNotFound) {
+					errorQuit(ctx, err)
+				}
+				if isDB {
+					io.Errorf("It is currently impossible to use `db-tunnel` on a database (%s).\n", app)
+					return nil
+				}
+				utils.CheckForConsent(ctx, app, utils.ConsentTypeDBs)
+			}
 
 			err = db.Tunnel(ctx, db.TunnelOpts{
 				App:       currentResource,
@@ -91,7 +151,11 @@ var (
 				Identity:  sshIdentity,
 				Port:      c.Int("port"),
 				Bind:      c.String("bind"),
+				Socket:    c.String("socket"),
 				Reconnect: c.Bool("reconnect"),
+				Targets:   targets,
+
+				KeepAliveInterval: c.Duration("keepalive-interval"),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
@@ -104,3 +168,22 @@ var (
 		},
 	}
 )
+
+// tunnelApps returns the distinct apps whose databases are tunneled. The
+// targets without app are databases of the current app.
+func tunnelApps(currentResource string, targets []db.TunnelTarget) []string {
+	if len(targets) == 0 {
+		return []string{currentResource}
+	}
+	var apps []string
+	for _, target := range targets {
+		app := target.App
+		if app == "" {
+			app = currentResource
+		}
+		if !slices.Contains(apps, app) {
+			apps = append(apps, app)
+		}
+	}
+	return apps
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/cli/db"
+)
+
+func TestTunnelApps(t *testing.T) {
+	tests := map[string]struct {
+		targets      []db.TunnelTarget
+		expectedApps []string
+	}{
+		"without target": {
+			expectedApps: []string{"my-app"},
+		},
+		"targets of the current app": {
+			targets:      []db.TunnelTarget{{DBEnvVar: "SCALINGO_POSTGRESQL_URL"}, {DBEnvVar: "SCALINGO_REDIS_URL"}},
+			expectedApps: []string{"my-app"},
+		},
+		"targets of other apps": {
+			targets: []db.TunnelTarget{
+				{DBEnvVar: "SCALINGO_POSTGRESQL_URL"},
+				{App: "other-app", DBEnvVar: "SCALINGO_POSTGRESQL_URL"},
+				{App: "other-app", DBEnvVar: "SCALINGO_REDIS_URL"},
+			},
+			expectedApps: []string{"my-app", "other-app"},
+		},
+		"only targets of another app": {
+			targets:      []db.TunnelTarget{{App: "other-app", DBEnvVar: "SCALINGO_POSTGRESQL_URL"}},
+			expectedApps: []string{"other-app"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedApps, tunnelApps("my-app", test.targets))
+		})
+	}
+}
@@ -27,7 +27,7 @@ var (
 				"scalingo database-firewall-rules my-db-id",
 				"scalingo --database my-db database-firewall-rules",
 			},
-			SeeAlso: []string{"database-firewall-rules-add", "database-firewall-rules-remove", "database-firewall-managed-ranges"},
+			SeeAlso: []string{"database-firewall-rules-add", "database-firewall-rules-remove", "database-firewall-rules-sync", "database-firewall-managed-ranges"},
 		}.Render(),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			databaseName, addonID, err := detect.GetDatabaseFromArgs(ctx, c)
@@ -192,6 +192,75 @@ var (
 		},
 	}
 
+	databaseFirewallRulesSyncCommand = cli.Command{
+		Name:      "database-firewall-rules-sync",
+		Category:  "Databases DR",
+		Usage:     "Sync the firewall rules of a database with a list of CIDRs",
+		ArgsUsage: "database-id",
+		Flags: []cli.Flag{
+			databaseFlag(),
+			&cli.StringFlag{Name: "file", Aliases: []string{"f"}, Usage: "File listing the allowed CIDRs", Required: true},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Only display the rules which would be added and removed"},
+			&cli.BoolFlag{Name: "add-my-ip", Usage: "Also allow your public IP address"},
+			&cli.BoolFlag{Name: "allow-empty", Usage: "Allow a file without any CIDR, removing all the custom rules"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Do not ask for confirmation before removing rules"},
+			&cli.StringFlag{
+				Name:    "my-ip-endpoint",
+				Usage:   "URL returning your public IP address in plain text",
+				Value:   dbng.DefaultMyIPEndpoint,
+				Sources: cli.EnvVars("SCALINGO_MY_IP_ENDPOINT"),
+			},
+		},
+		Description: CommandDescription{
+			Description: `Sync the custom firewall rules of a database with a list of CIDRs
+
+Each line of the file is a CIDR or an IP address, optionally followed by a label. Blank lines and lines starting with '#' are ignored:
+
+    # Office
+    203.0.113.0/24 Office network
+    198.51.100.12 CI runner
+
+The missing rules are added, then the custom rules which are not in the file are removed, after confirmation unless '--yes' is given. The managed range rules are left untouched. The labels of the existing rules are not updated.
+
+A file without any CIDR is refused, as it would remove all the custom rules, unless '--allow-empty' is given.`,
+			Examples: []string{
+				"scalingo --database my-db database-firewall-rules-sync --file allowed.txt --dry-run",
+				"scalingo database-firewall-rules-sync my-db-id --file allowed.txt --add-my-ip",
+			},
+			SeeAlso: []string{"database-firewall-rules", "database-firewall-rules-add", "database-firewall-rules-remove"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			databaseName, addonID, err := detect.GetDatabaseFromArgs(ctx, c)
+			if errors.Is(err, detect.ErrTooManyArguments) {
+				io.Error(err)
+				return cli.ShowCommandHelp(ctx, c, "database-firewall-rules-sync")
+			}
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+
+			utils.CheckForConsent(ctx, databaseName, utils.ConsentTypeDBs)
+
+			err = dbng.FirewallRulesSync(ctx, databaseName, addonID, dbng.FirewallRulesSyncOpts{
+				File:         c.String("file"),
+				DryRun:       c.Bool("dry-run"),
+				AddMyIP:      c.Bool("add-my-ip"),
+				MyIPEndpoint: c.String("my-ip-endpoint"),
+				AllowEmpty:   c.Bool("allow-empty"),
+				Yes:          c.Bool("yes"),
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+
+			return nil
+		},
+		ShellComplete: func(ctx context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "database-firewall-rules-sync")
+			_ = autocomplete.DatabasesNgListAutoComplete(ctx)
+		},
+	}
+
 	databaseFirewallManagedRangesCommand = cli.Command{
 		Name:      "database-firewall-managed-ranges",
 		Category:  "Databases DR",
@@ -3,9 +3,11 @@ package cmd
 import (
 	"context"
 	"os"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/Scalingo/cli/db"
 	"github.com/Scalingo/cli/detect"
 	"github.com/Scalingo/cli/io"
 	"github.com/Scalingo/cli/utils"
@@ -47,6 +49,34 @@ func databaseNameFromFlags(c *cli.Command) string {
 	return ""
 }
 
+// isValidProjectSlug returns true if the project filter is empty or has the
+// format <ownerUsername>/<projectName>
+func isValidProjectSlug(projectSlug string) bool {
+	if projectSlug == "" {
+		return true
+	}
+	owner, project, ok := strings.Cut(projectSlug, "/")
+	return ok && owner != "" && project != "" && !strings.Contains(project, "/")
+}
+
+// consoleQueryFlags returns the flags of the database consoles to execute a
+// query instead of opening an interactive console.
+func consoleQueryFlags(formats ...string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{Name: "command", Aliases: []string{"c"}, Usage: "Query to execute instead of opening an interactive console"},
+		&cli.StringFlag{Name: "file", Aliases: []string{"f"}, Usage: "Local script to execute instead of opening an interactive console"},
+		&cli.StringFlag{Name: "format", Usage: "Output format of the query (" + strings.Join(formats, ", ") + ")", DefaultText: formats[0]},
+	}
+}
+
+func consoleQueryFromFlags(c *cli.Command) db.ConsoleQueryOpts {
+	return db.ConsoleQueryOpts{
+		Command: c.String("command"),
+		File:    c.String("file"),
+		Format:  c.String("format"),
+	}
+}
+
 // addonUUIDFromFlags returns the addon UUID based on the --addon flag, the SCALINGO_ADDON env var
 // or the current detected database.
 // exitIfMissing is optional. Set to true to show a message requesting for the --addon flag.
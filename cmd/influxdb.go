@@ -17,21 +17,28 @@ var (
 		Aliases:  []string{"influx-console"},
 		Category: "Databases",
 		Usage:    "Run an interactive console with your InfluxDB addon",
-		Flags: []cli.Flag{&appFlag,
+		Flags: append([]cli.Flag{&appFlag,
 			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the container"},
 			&cli.StringFlag{Name: "env", Aliases: []string{"e"}, Value: "", Usage: "Environment variable name to use for the connection to the database"},
-		},
+		}, consoleQueryFlags(db.ConsoleFormatTable, db.ConsoleFormatCSV, db.ConsoleFormatJSON)...),
 		Description: CommandDescription{
 			Description: `Run an interactive console with your InfluxDB addon
 
 The --size flag makes it easy to specify the size of the container executing
 the InfluxDB console. Each container size has different price and performance.
 You can read more about container sizes here:
-http://doc.scalingo.com/internals/container-sizes.html`,
+http://doc.scalingo.com/internals/container-sizes.html
+
+The --command and --file flags execute a query or a local script with influx
+instead of opening an interactive console. Only the output of influx is
+displayed, in the format given with --format, and the command exits with
+the status of influx.`,
 			Examples: []string{
 				"scalingo --app my-app influxdb-console",
 				"scalingo --app my-app influxdb-console --size L",
 				"scalingo --app my-app influxdb-console --env MY_INFLUXDB_URL",
+				`scalingo --app my-app influxdb-console --command "SHOW MEASUREMENTS" --format json`,
+				"scalingo --app my-app influxdb-console --file queries.txt --format csv",
 			},
 			SeeAlso: []string{"mongo-console", "mysql-console"},
 		}.Render(),
@@ -48,6 +55,7 @@ http://doc.scalingo.com/internals/container-sizes.html`,
 				App:          currentApp,
 				Size:         c.String("s"),
 				VariableName: c.String("e"),
+				Query:        consoleQueryFromFlags(c),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
+	"github.com/Scalingo/cli/cmd/autocomplete"
 	"github.com/Scalingo/cli/db/maintenance"
 	"github.com/Scalingo/cli/detect"
 	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
 	"github.com/Scalingo/go-utils/pagination"
 )
 
@@ -85,3 +88,80 @@ var databaseMaintenanceInfo = cli.Command{
 		return nil
 	},
 }
+
+var maintenanceCalendar = cli.Command{
+	Name:     "maintenance-calendar",
+	Category: "Addons Maintenance",
+	Usage:    "Display the maintenance windows and the pending maintenance of all your databases",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "project", Usage: "Only consider the apps of a project. The filter uses the format <ownerUsername>/<projectName>"},
+		&cli.StringFlag{Name: "tz", Usage: "Timezone of the displayed dates, of the freeze periods and of --move-conflicts-to", DefaultText: "local timezone"},
+		&cli.IntFlag{Name: "weeks", Usage: "Number of weeks of maintenance windows to display", Value: 4},
+		&cli.StringFlag{Name: "ical", Usage: "Export the calendar to an iCalendar file (- for stdout)"},
+		&cli.StringFlag{Name: "freeze-periods", Usage: "YAML file listing the periods during which no maintenance should happen"},
+		&cli.StringFlag{Name: "move-conflicts-to", Usage: "Move the maintenance windows overlapping a freeze period to <weekday>:<hour>"},
+		&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Do not ask for confirmation before moving the maintenance windows"},
+	},
+	Description: CommandDescription{
+		Description: `Display the maintenance windows and the pending maintenance of the database addons of all your apps
+
+The next occurrences of the maintenance windows are listed by date to spot the days
+when many databases are impacted. The calendar can be exported with '--ical' to be
+imported in a calendar application: each maintenance window is a weekly recurring
+event, and each pending maintenance is planned during the next maintenance window.
+
+Freeze periods (e.g. a sales season) are read from a YAML file:
+
+  freeze_periods:
+    - name: Black Friday
+      start: 2026-11-26
+      end: 2026-11-30
+    - name: Release
+      start: 2026-12-03 08:00
+      end: 2026-12-03 20:00
+
+A date without time includes the whole day. The maintenance windows overlapping a
+freeze period are reported, and '--move-conflicts-to' moves all of them to a new
+starting day and hour after a confirmation.`,
+		Examples: []string{
+			"scalingo maintenance-calendar",
+			"scalingo maintenance-calendar --project my-user/my-project --tz Europe/Paris",
+			"scalingo maintenance-calendar --ical maintenance.ics",
+			"scalingo maintenance-calendar --freeze-periods freeze.yml --move-conflicts-to sunday:3",
+		},
+		SeeAlso: []string{"database-maintenance-list", "addons-config"},
+	}.Render(),
+
+	Action: func(ctx context.Context, c *cli.Command) error {
+		projectSlug := c.String("project")
+		if !isValidProjectSlug(projectSlug) {
+			errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "maintenance-calendar")
+		}
+
+		location := time.Local
+		if c.String("tz") != "" {
+			var err error
+			location, err = time.LoadLocation(c.String("tz"))
+			if err != nil {
+				errorQuit(ctx, errors.Wrapf(ctx, err, "invalid timezone '%s'", c.String("tz")))
+			}
+		}
+
+		err := maintenance.Calendar(ctx, maintenance.CalendarOpts{
+			ProjectSlug:       projectSlug,
+			Location:          location,
+			Weeks:             c.Int("weeks"),
+			ICalendarFile:     c.String("ical"),
+			FreezePeriodsFile: c.String("freeze-periods"),
+			MoveConflictsTo:   c.String("move-conflicts-to"),
+			Yes:               c.Bool("yes"),
+		})
+		if err != nil {
+			errorQuit(ctx, err)
+		}
+		return nil
+	},
+	ShellComplete: func(_ context.Context, c *cli.Command) {
+		_ = autocomplete.CmdFlagsAutoComplete(c, "maintenance-calendar")
+	},
+}
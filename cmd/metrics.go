@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/detect"
+	"github.com/Scalingo/cli/metrics"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+var metricsCommand = cli.Command{
+	Name:     "metrics",
+	Category: "Display metrics of the running containers",
+	Usage:    "Display the time series of a metric of an application",
+	Flags: []cli.Flag{
+		&appFlag,
+		&cli.StringFlag{Name: "metric", Aliases: []string{"m"}, Value: "cpu", Usage: "Metric to display (" + strings.Join(append(metrics.ContainerMetrics, metrics.RouterMetrics...), ", ") + ")"},
+		&cli.StringFlag{Name: "since", Value: "1h", Usage: "Duration of the time series, e.g. 30m, 6h or 7d"},
+		&cli.StringFlag{Name: "type", Usage: "Only display the containers of this type", DefaultText: "all container types"},
+		&cli.StringFlag{Name: "aggregate", Value: metrics.AggregateType, Usage: "One series per container type or per container [" + metrics.AggregateType + "|" + metrics.AggregateIndex + "]"},
+		&cli.StringFlag{Name: "chart", Value: metrics.ChartSparkline, Usage: "Chart of the series [" + metrics.ChartSparkline + "|" + metrics.ChartLine + "]"},
+		&cli.StringFlag{Name: "format", Value: metrics.FormatChart, Usage: "Output format [" + metrics.FormatChart + "|" + metrics.FormatJSON + "|" + metrics.FormatCSV + "]"},
+	},
+	Description: CommandDescription{
+		Description: `Display the time series of a metric of an application
+
+The container metrics (cpu, memory, swap) are displayed per container type, or per container with '--aggregate index'. The router metrics are the metrics of the requests to the web containers.
+
+The charts are sized to the width of the terminal. The series can be exported with '--format json' or '--format csv'.`,
+		Examples: []string{
+			"scalingo --app my-app metrics --metric memory --since 6h",
+			"scalingo --app my-app metrics --metric cpu --type web --aggregate index --chart line",
+			"scalingo --app my-app metrics --metric p95_response_time --since 1d --format csv > p95.csv",
+		},
+		SeeAlso: []string{"stats"},
+	}.Render(),
+	Action: func(ctx context.Context, c *cli.Command) error {
+		currentApp := detect.CurrentApp(ctx, c)
+		if c.Args().Len() != 0 {
+			return cli.ShowCommandHelp(ctx, c, "metrics")
+		}
+
+		since, err := metrics.ParseSince(ctx, c.String("since"))
+		if err != nil {
+			errorQuitWithHelpMessage(ctx, err, c, "metrics")
+		}
+		aggregate := c.String("aggregate")
+		if aggregate != metrics.AggregateType && aggregate != metrics.AggregateIndex {
+			errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid aggregate '%s'", aggregate), c, "metrics")
+		}
+		chart := c.String("chart")
+		if chart != metrics.ChartSparkline && chart != metrics.ChartLine {
+			errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid chart '%s'", chart), c, "metrics")
+		}
+		format := c.String("format")
+		if format != metrics.FormatChart && format != metrics.FormatJSON && format != metrics.FormatCSV {
+			errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid format '%s'", format), c, "metrics")
+		}
+
+		err = metrics.Show(ctx, currentApp, metrics.ShowOpts{
+			Metric:        c.String("metric"),
+			Since:         since,
+			ContainerType: c.String("type"),
+			Aggregate:     aggregate,
+			Chart:         chart,
+			Format:        format,
+		})
+		if err != nil {
+			errorQuit(ctx, err)
+		}
+		return nil
+	},
+}
@@ -17,20 +17,29 @@ var (
 		Aliases:  []string{"mongodb-console"},
 		Category: "Databases",
 		Usage:    "Run an interactive console with your MongoDB addon",
-		Flags: []cli.Flag{&appFlag,
+		Flags: append([]cli.Flag{&appFlag,
 			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the container"},
 			&cli.StringFlag{Name: "env", Aliases: []string{"e"}, Value: "", Usage: "Environment variable name to use for the connection to the database"},
-		},
+		}, consoleQueryFlags(db.ConsoleFormatTable, db.ConsoleFormatJSON)...),
 		Description: CommandDescription{
 			Description: `Run an interactive console with your MongoDB addon
 The --size flag makes it easy to specify the size of the container executing
 the MongoDB console. Each container size has different price and performance.
 You can read more about container sizes here:
-http://doc.scalingo.com/internals/container-sizes.html`,
+http://doc.scalingo.com/internals/container-sizes.html
+
+The --command and --file flags execute a query or a local script with mongo
+instead of opening an interactive console. Only the output of mongo is
+displayed, in the format given with --format, and the command exits with
+the status of mongo.
+The JSON format serializes the result of the command, it is not available
+with --file.`,
 			Examples: []string{
 				"scalingo --app my-app mongo-console",
 				"scalingo --app my-app mongo-console --size L",
 				"scalingo --app my-app mongo-console --env MY_MONGO_URL",
+				`scalingo --app my-app mongo-console --command "db.users.find({admin: true})" --format json`,
+				"scalingo --app my-app mongo-console --file script.js",
 			},
 			SeeAlso: []string{"redis-console", "mysql-console"},
 		}.Render(),
@@ -47,6 +56,7 @@ http://doc.scalingo.com/internals/container-sizes.html`,
 				App:          currentApp,
 				Size:         c.String("s"),
 				VariableName: c.String("e"),
+				Query:        consoleQueryFromFlags(c),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
@@ -16,21 +16,28 @@ var (
 		Name:     "mysql-console",
 		Category: "Databases",
 		Usage:    "Run an interactive console with your MySQL addon",
-		Flags: []cli.Flag{&appFlag,
+		Flags: append([]cli.Flag{&appFlag,
 			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the container"},
 			&cli.StringFlag{Name: "env", Aliases: []string{"e"}, Value: "", Usage: "Environment variable name to use for the connection to the database"},
-		},
+		}, consoleQueryFlags(db.ConsoleFormatTable, db.ConsoleFormatTSV)...),
 		Description: CommandDescription{
 			Description: `Run an interactive console with your MySQL addon
 
 The --size flag makes it easy to specify the size of the container executing
 the MySQL console. Each container size has different price and performance.
 You can read more about container sizes here:
-http://doc.scalingo.com/internals/container-sizes.html`,
+http://doc.scalingo.com/internals/container-sizes.html
+
+The --command and --file flags execute a query or a local script with mysql
+instead of opening an interactive console. Only the output of mysql is
+displayed, in the format given with --format, and the command exits with
+the status of mysql.`,
 			Examples: []string{
 				"scalingo --app my-app mysql-console",
 				"scalingo --app my-app mysql-console --size L",
 				"scalingo --app my-app mysql-console --env MY_MYSQL_URL",
+				`scalingo --app my-app mysql-console --command "SELECT count(*) FROM users" --format tsv`,
+				"scalingo --app my-app mysql-console --file migration.sql",
 			},
 			SeeAlso: []string{"mongo-console", "pgsql-console"},
 		}.Render(),
@@ -47,6 +54,7 @@ http://doc.scalingo.com/internals/container-sizes.html`,
 				App:          currentApp,
 				Size:         c.String("s"),
 				VariableName: c.String("e"),
+				Query:        consoleQueryFromFlags(c),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
@@ -17,21 +17,30 @@ var (
 		Aliases:  []string{"psql-console", "postgresql-console"},
 		Category: "Databases",
 		Usage:    "Run an interactive console with your PostgreSQL addon",
-		Flags: []cli.Flag{&appFlag,
+		Flags: append([]cli.Flag{&appFlag,
 			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the container"},
 			&cli.StringFlag{Name: "env", Aliases: []string{"e"}, Value: "", Usage: "Environment variable name to use for the connection to the database"},
-		},
+		}, consoleQueryFlags(db.ConsoleFormatTable, db.ConsoleFormatCSV, db.ConsoleFormatJSON)...),
 		Description: CommandDescription{
 			Description: `Run an interactive console with your PostgreSQL addon
 
 The --size flag makes it easy to specify the size of the container executing
 the PostgreSQL console. Each container size has different price and performance.
 You can read more about container sizes here:
-http://doc.scalingo.com/internals/container-sizes.html`,
+http://doc.scalingo.com/internals/container-sizes.html
+
+The --command and --file flags execute a query or a local script with psql
+instead of opening an interactive console. Only the output of psql is
+displayed, in the format given with --format, and the command exits with
+the status of psql.
+The JSON format aggregates the rows returned by the command, it is not
+available with --file.`,
 			Examples: []string{
 				"scalingo --app my-app pgsql-console",
 				"scalingo --app my-app pgsql-console --size L",
 				"scalingo --app my-app pgsql-console --env MY_PSQL_URL",
+				`scalingo --app my-app pgsql-console --command "SELECT count(*) FROM users" --format csv`,
+				"scalingo --app my-app pgsql-console --file migration.sql",
 			},
 			SeeAlso: []string{"mongo-console", "mysql-console"},
 		}.Render(),
@@ -49,6 +58,7 @@ http://doc.scalingo.com/internals/container-sizes.html`,
 				App:          currentApp,
 				Size:         c.String("s"),
 				VariableName: c.String("e"),
+				Query:        consoleQueryFromFlags(c),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
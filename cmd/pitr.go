@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli/v3"
@@ -20,15 +21,42 @@ var databasePITRRestore = cli.Command{
 	Flags: []cli.Flag{
 		&appFlag,
 		&addonFlag,
+		&cli.StringFlag{Name: "tz", Usage: "Timezone of the restore time and of the displayed dates", DefaultText: "local timezone"},
+		&cli.StringFlag{Name: "confirm", Usage: "Name of the app, to skip the interactive confirmation"},
 	},
 	Description: CommandDescription{
-		Description: "Restore a database to a specific point in time",
+		Description: `Restore a database to a specific point in time
+
+The restore time can be an RFC 3339 time, a time without timezone interpreted in the timezone given with '--tz', or a time relative to now like '15 minutes ago'. It must be inside the recovery window of the database.
+
+A preview of the restore with the nearest backups is displayed, and the name of the app must be typed to confirm, or given with '--confirm'. The restore operation is then followed until the database is running again.`,
 		Examples: []string{
 			"scalingo --app my-app --addon my-addon database-pitr-restore 2026-07-18T23:00:00Z",
+			"scalingo --app my-app --addon my-addon database-pitr-restore --tz Europe/Paris '2026-07-18 23:00'",
+			"scalingo --app my-app --addon my-addon database-pitr-restore 15 minutes ago",
 		},
+		SeeAlso: []string{"database-pitr-recovery-window", "backups"},
 	}.Render(),
 	Action: func(ctx context.Context, c *cli.Command) error {
 		currentResource, currentDatabase := detect.GetCurrentResourceAndDatabase(ctx, c)
+		if c.Args().Len() == 0 {
+			errorQuitWithHelpMessage(ctx, errors.New(ctx, "missing restore-time"), c, "database-pitr-restore")
+		}
+
+		location := time.Local
+		if c.String("tz") != "" {
+			var err error
+			location, err = time.LoadLocation(c.String("tz"))
+			if err != nil {
+				errorQuit(ctx, errors.Wrapf(ctx, err, "invalid timezone '%s'", c.String("tz")))
+			}
+		}
+
+		// The arguments are joined to accept unquoted relative times
+		restoreTime, err := pitr.ParseRestoreTime(ctx, strings.Join(c.Args().Slice(), " "), time.Now(), location)
+		if err != nil {
+			errorQuitWithHelpMessage(ctx, err, c, "database-pitr-restore")
+		}
 
 		utils.CheckForConsent(ctx, currentResource, utils.ConsentTypeDBs)
 		addonName := currentDatabase
@@ -36,12 +64,11 @@ var databasePITRRestore = cli.Command{
 			addonName = addonUUIDFromFlags(ctx, c, currentResource, true)
 		}
 
-		restoreTimeStr, err := time.Parse(time.RFC3339, c.Args().First())
-		if err != nil {
-			errorQuitWithHelpMessage(ctx, errors.Wrap(ctx, err, "invalid restore-time format"), c, "database-pitr-restore")
-		}
-
-		err = pitr.Restore(ctx, currentResource, addonName, restoreTimeStr)
+		err = pitr.Restore(ctx, currentResource, addonName, pitr.RestoreOpts{
+			RestoreTime: restoreTime,
+			Location:    location,
+			Confirm:     c.String("confirm"),
+		})
 		if err != nil {
 			errorQuit(ctx, err)
 		}
@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/cmd/autocomplete"
+	"github.com/Scalingo/cli/crypto/sshkeys"
+	"github.com/Scalingo/cli/detect"
+	"github.com/Scalingo/cli/portforward"
+	"github.com/Scalingo/cli/utils"
+)
+
+var (
+	portForwardCommand = cli.Command{
+		Name:      "port-forward",
+		Category:  "Private Networks",
+		Usage:     "Forward local ports to hosts reachable from the SSH gateway",
+		ArgsUsage: "[bind:]port:host:hostport...",
+		Flags: []cli.Flag{&appFlag,
+			&cli.StringFlag{Name: "identity", Aliases: []string{"i"}, Usage: "SSH Private Key"},
+			&cli.BoolFlag{Name: "reconnect", Value: true, Usage: "true by default, automatically reconnect when disconnected"},
+			&cli.DurationFlag{Name: "keepalive-interval", Value: 30 * time.Second, Usage: "Interval between two SSH keepalives, 0 to disable them"},
+		},
+		Description: CommandDescription{
+			Description: `Forward local ports to any host reachable through the SSH gateway of the region, for instance the private network domain names of an application.
+
+Each forwarding is written '[bind:]port:host:hostport', like the '-L' option of OpenSSH, with the IPv6 addresses enclosed in square brackets. The local address is bound to 127.0.0.1 by default. All the forwardings share a single SSH connection, authenticated with your SSH key like 'db-tunnel'.`,
+			Examples: []string{
+				"scalingo --app my-app port-forward 8080:1.web.ap-xxx.pn-xxx.private-network.internal:8080",
+				"scalingo --app my-app port-forward 0.0.0.0:9000:2.worker.ap-xxx.pn-xxx.private-network.internal:9000 9001:my-host:9001",
+			},
+			SeeAlso: []string{"private-networks-domain-names", "socks-proxy", "db-tunnel"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() == 0 {
+				_ = cli.ShowCommandHelp(ctx, c, "port-forward")
+				return nil
+			}
+
+			forwardings := make([]portforward.Forwarding, 0, c.Args().Len())
+			for _, arg := range c.Args().Slice() {
+				forwarding, err := portforward.ParseForwarding(ctx, arg)
+				if err != nil {
+					errorQuit(ctx, err)
+				}
+				forwardings = append(forwardings, forwarding)
+			}
+
+			currentApp := detect.CurrentApp(ctx, c)
+			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
+
+			err := portforward.Forward(ctx, forwardings, portForwardOptsFromFlags(c))
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "port-forward")
+		},
+	}
+
+	socksProxyCommand = cli.Command{
+		Name:     "socks-proxy",
+		Category: "Private Networks",
+		Usage:    "Start a SOCKS5 proxy through the SSH gateway",
+		Flags: []cli.Flag{&appFlag,
+			&cli.IntFlag{Name: "port", Aliases: []string{"p"}, Value: 1080, Usage: "Local port to bind"},
+			&cli.StringFlag{Name: "bind", Aliases: []string{"b"}, Usage: "IP to bind (default 127.0.0.1)"},
+			&cli.BoolFlag{Name: "allow-remote", Usage: "Allow to bind a non-loopback IP, the proxy is then usable by anyone reaching it"},
+			&cli.StringFlag{Name: "identity", Aliases: []string{"i"}, Usage: "SSH Private Key"},
+			&cli.BoolFlag{Name: "reconnect", Value: true, Usage: "true by default, automatically reconnect when disconnected"},
+			&cli.DurationFlag{Name: "keepalive-interval", Value: 30 * time.Second, Usage: "Interval between two SSH keepalives, 0 to disable them"},
+		},
+		Description: CommandDescription{
+			Description: `Start a local SOCKS5 proxy. Every connection requested to the proxy is opened from the SSH gateway of the region, so that any host reachable from it (e.g. the private network domain names of an application) can be accessed by tools supporting SOCKS5.
+
+The proxy has no authentication. It is bound to 127.0.0.1 by default and binding it to a non-loopback IP with '--bind' requires '--allow-remote'.`,
+			Examples: []string{
+				"scalingo --app my-app socks-proxy --port 1080",
+				"curl --socks5-hostname 127.0.0.1:1080 http://1.web.ap-xxx.pn-xxx.private-network.internal:8080",
+			},
+			SeeAlso: []string{"private-networks-domain-names", "port-forward"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			if c.Args().Len() > 0 {
+				_ = cli.ShowCommandHelp(ctx, c, "socks-proxy")
+				return nil
+			}
+
+			currentApp := detect.CurrentApp(ctx, c)
+			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
+
+			err := portforward.SOCKSProxy(ctx, portforward.SOCKSProxyOpts{
+				Bind:        c.String("bind"),
+				Port:        c.Int("port"),
+				AllowRemote: c.Bool("allow-remote"),
+			}, portForwardOptsFromFlags(c))
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "socks-proxy")
+		},
+	}
+)
+
+func portForwardOptsFromFlags(c *cli.Command) portforward.Opts {
+	identity := c.String("identity")
+	if identity == "" && os.Getenv("SSH_AUTH_SOCK") != "" {
+		identity = "ssh-agent"
+	} else if identity == "" {
+		identity = sshkeys.DefaultKeyPath
+	}
+
+	return portforward.Opts{
+		Identity:          identity,
+		Reconnect:         c.Bool("reconnect"),
+		KeepAliveInterval: c.Duration("keepalive-interval"),
+	}
+}
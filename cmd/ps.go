@@ -8,6 +8,7 @@ import (
 	"github.com/Scalingo/cli/apps"
 	"github.com/Scalingo/cli/cmd/autocomplete"
 	"github.com/Scalingo/cli/detect"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 var (
@@ -15,10 +16,21 @@ var (
 		Name:     "ps",
 		Category: "App Management",
 		Usage:    "Display your application containers",
-		Flags:    []cli.Flag{&appFlag},
+		Flags: []cli.Flag{
+			&appFlag,
+			&cli.BoolFlag{Name: "watch", Aliases: []string{"w"}, Usage: "Watch the containers and print their lifecycle events"},
+			&cli.DurationFlag{Name: "interval", Value: apps.DefaultPsWatchInterval, Usage: "Interval between two polls of the containers with --watch"},
+			&cli.StringFlag{Name: "format", Value: apps.PsFormatTable, Usage: "Output format of the events with --watch [" + apps.PsFormatTable + "|" + apps.PsFormatJSON + "]"},
+		},
 		Description: CommandDescription{
-			Description: "Display your application containers",
-			Examples:    []string{"scalingo --app my-app ps"},
+			Description: `Display your application containers
+
+With '--watch', the containers are polled and their changes are printed as events: started, restarted, crashed, stopped, finished for one-off containers, or a change of state. Each event carries its time, the number of restarts of the container since the beginning of the watch and its uptime. A crash is hinted as a possible out of memory if the container was close to its memory limit. With '--format json', an event is printed per line.`,
+			Examples: []string{
+				"scalingo --app my-app ps",
+				"scalingo --app my-app ps --watch",
+				"scalingo --app my-app ps --watch --format json --interval 10s",
+			},
 		}.Render(),
 
 		Action: func(ctx context.Context, c *cli.Command) error {
@@ -28,7 +40,17 @@ var (
 				return nil
 			}
 
-			err := apps.Ps(ctx, currentApp)
+			format := c.String("format")
+			if format != apps.PsFormatTable && format != apps.PsFormatJSON {
+				errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "invalid format '%s'", format), c, "ps")
+			}
+
+			var err error
+			if c.Bool("watch") {
+				err = apps.PsWatch(ctx, currentApp, apps.PsWatchOpts{Interval: c.Duration("interval"), Format: format})
+			} else {
+				err = apps.Ps(ctx, currentApp)
+			}
 			if err != nil {
 				errorQuit(ctx, err)
 			}
@@ -16,21 +16,28 @@ var (
 		Name:     "redis-console",
 		Category: "Databases",
 		Usage:    "Run an interactive console with your Redis addon",
-		Flags: []cli.Flag{&appFlag,
+		Flags: append([]cli.Flag{&appFlag,
 			&cli.StringFlag{Name: "size", Aliases: []string{"s"}, Value: "", Usage: "Size of the container"},
 			&cli.StringFlag{Name: "env", Aliases: []string{"e"}, Value: "", Usage: "Environment variable name to use for the connection to the database"},
-		},
+		}, consoleQueryFlags(db.ConsoleFormatTable, db.ConsoleFormatCSV)...),
 		Description: CommandDescription{
 			Description: `Run an interactive console with your Redis addon.
 
 The --size flag makes it easy to specify the size of the container executing
 the Redis console. Each container size has different price and performance.
 You can read more about container sizes here:
-http://doc.scalingo.com/internals/container-sizes.html`,
+http://doc.scalingo.com/internals/container-sizes.html
+
+The --command and --file flags execute a query or a local script with redis-cli
+instead of opening an interactive console. Only the output of redis-cli is
+displayed, in the format given with --format, and the command exits with
+the status of redis-cli.`,
 			Examples: []string{
 				"scalingo --app my-app redis-console",
 				"scalingo --app my-app redis-console --size L",
 				"scalingo --app my-app redis-console --env MY_REDIS_URL",
+				`scalingo --app my-app redis-console --command "INFO memory"`,
+				"scalingo --app my-app redis-console --file commands.txt --format csv",
 			},
 			SeeAlso: []string{"mongo-console", "mysql-console"},
 		}.Render(),
@@ -48,6 +55,7 @@ http://doc.scalingo.com/internals/container-sizes.html`,
 				App:          currentApp,
 				Size:         c.String("s"),
 				VariableName: c.String("e"),
+				Query:        consoleQueryFromFlags(c),
 			})
 			if err != nil {
 				errorQuit(ctx, err)
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/Scalingo/cli/cmd/autocomplete"
 	"github.com/Scalingo/cli/detect"
 	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 var (
@@ -18,13 +20,22 @@ var (
 		Usage:    "Restart processes of your app",
 		Flags: []cli.Flag{&appFlag,
 			&cli.BoolFlag{Name: "synchronous", Aliases: []string{"s"}, Usage: "Do the restart synchronously"},
+			&cli.BoolFlag{Name: "rolling", Usage: "Restart the containers batch by batch, waiting for each batch to be healthy"},
+			&cli.StringSliceFlag{Name: "type", Usage: "Container type restarted by the rolling restart (can be repeated)", DefaultText: "all container types"},
+			&cli.IntFlag{Name: "batch", Value: 1, Usage: "Number of containers restarted at once by the rolling restart"},
+			&cli.DurationFlag{Name: "wait-healthy", Value: 30 * time.Second, Usage: "Maximal duration for a batch to be healthy"},
+			&cli.StringFlag{Name: "health-url", Usage: "URL which must respond successfully for a batch to be healthy"},
 		},
 		Description: CommandDescription{
-			Description: "Restart one or several process or your application",
+			Description: `Restart one or several process or your application
+
+With '--rolling', the containers are restarted batch by batch. A batch is healthy once its containers are running and the '--health-url' responds with a success status. The restart stops with an error if a batch is not healthy after '--wait-healthy'.`,
 			Examples: []string{
 				"scalingo --app my-app restart        # Restart all the processes",
 				"scalingo --app my-app restart web    # Restart all the web processes",
 				"scalingo --app my-app restart web-1  # Restart a specific container",
+				"scalingo --app my-app restart --rolling --type web --batch 1 --wait-healthy 30s",
+				"scalingo --app my-app restart --rolling --type web --health-url https://my-app.osc-fr1.scalingo.io/health",
 			},
 		}.Render(),
 
@@ -32,6 +43,24 @@ var (
 			currentApp := detect.CurrentApp(ctx, c)
 			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
 
+			if c.Bool("rolling") {
+				if c.Int("batch") < 1 {
+					errorQuitWithHelpMessage(ctx, errors.New(ctx, "--batch must be at least 1"), c, "restart")
+				}
+				// The container types can also be given as arguments
+				types := append(c.StringSlice("type"), c.Args().Slice()...)
+				err := apps.RollingRestart(ctx, currentApp, apps.RollingRestartOpts{
+					Types:       types,
+					Batch:       c.Int("batch"),
+					WaitHealthy: c.Duration("wait-healthy"),
+					HealthURL:   c.String("health-url"),
+				})
+				if err != nil {
+					errorQuit(ctx, err)
+				}
+				return nil
+			}
+
 			err := apps.Restart(ctx, currentApp, c.Bool("s"), c.Args().Slice())
 			if err != nil {
 				errorQuit(ctx, err)
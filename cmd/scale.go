@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"time"
 
 	"github.com/urfave/cli/v3"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/Scalingo/cli/cmd/autocomplete"
 	"github.com/Scalingo/cli/detect"
 	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 var (
@@ -18,23 +20,67 @@ var (
 		Category: "App Management",
 		Flags: []cli.Flag{&appFlag,
 			&cli.BoolFlag{Name: "synchronous", Aliases: []string{"s"}, Usage: "Do the scaling synchronously"},
+			&cli.BoolFlag{Name: "dry-run", Usage: "Display the current and target formations with their cost without scaling"},
+			&cli.BoolFlag{Name: "rollback-on-failure", Usage: "Restore the previous formation if the scale fails or if containers crash during the grace period"},
+			&cli.DurationFlag{Name: "grace-period", Value: time.Minute, Usage: "Duration during which crashed containers trigger the rollback"},
+			&cli.BoolFlag{Name: "yes", Aliases: []string{"y"}, Usage: "Confirm the deactivation of the autoscalers of the scaled container types"},
+			&cli.StringFlag{Name: "profile", Usage: "Scale to the formation of a profile of the profiles file"},
+			&cli.StringFlag{Name: "diff-profile", Usage: "Display the differences between the current formation and a profile"},
+			&cli.StringFlag{Name: "profiles-file", Value: apps.DefaultFormationProfilesFile, Usage: "YAML file of the formation profiles"},
 		},
 		Usage:     "Scale your application instantly",
 		ArgsUsage: "[scaling-instruction...]",
 		Description: CommandDescription{
-			Description: "Scale your application processes. Without argument, this command lists the container types declared in your application",
+			Description: `Scale your application processes. Without argument, this command lists the container types declared in your application
+
+With '--dry-run', the current and target formations are displayed with their estimated cost for 30 days, and the application is not scaled.
+
+With '--synchronous', the state of the containers is displayed until the end of the scale. With '--rollback-on-failure', the containers are also watched during the '--grace-period', and the previous formation is restored if the scale fails or if a container crashes.
+
+Manually scaling a container type disables its autoscaler: the confirmation is asked, unless '--yes' is given. '--yes' is required when the standard input is not a terminal.
+
+Named formations can be stored in a profiles file, and applied with '--profile' or compared to the current formation with '--diff-profile':
+
+    night:
+      worker: 10:L
+    day:
+      worker: 2:M
+      web: 2
+
+All the container types of a profile are scaled at once. The container types which are not in the profile are not modified.`,
 			Examples: []string{
 				"scalingo --app my-app scale web:2 worker:1",
 				"scalingo --app my-app scale web:1 worker:0",
 				"scalingo --app my-app scale web:1:XL",
 				"scalingo --app my-app scale web:+1 worker:-1",
+				"scalingo --app my-app scale web:4:L --dry-run",
+				"scalingo --app my-app scale web:4:L --rollback-on-failure --grace-period 2m",
+				"scalingo --app my-app scale --profile night --yes",
+				"scalingo --app my-app scale --diff-profile night --profiles-file config/profiles.yml",
 			},
+			SeeAlso: []string{"container-sizes", "cost"},
 		}.Render(),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			currentApp := detect.CurrentApp(ctx, c)
 			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
 
-			if c.Args().Len() == 0 {
+			profile, diffProfile := c.String("profile"), c.String("diff-profile")
+			if (profile != "" || diffProfile != "") && c.Args().Len() != 0 {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "scaling instructions can't be used with a profile"), c, "scale")
+			}
+			if profile != "" && diffProfile != "" {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "--profile and --diff-profile can't be used together"), c, "scale")
+			}
+
+			if diffProfile != "" {
+				err := apps.DiffProfile(ctx, currentApp, c.String("profiles-file"), diffProfile)
+				if err != nil {
+					errorQuit(ctx, err)
+				}
+				return nil
+			}
+
+			if c.Args().Len() == 0 && profile == "" {
 				err := apps.ContainerTypes(ctx, currentApp)
 				if err != nil {
 					errorQuit(ctx, err)
@@ -42,7 +88,19 @@ var (
 				return nil
 			}
 
-			err := apps.Scale(ctx, currentApp, c.Bool("s"), c.Args().Slice())
+			opts := apps.ScaleOpts{
+				Synchronous:       c.Bool("s"),
+				DryRun:            c.Bool("dry-run"),
+				RollbackOnFailure: c.Bool("rollback-on-failure"),
+				GracePeriod:       c.Duration("grace-period"),
+				Yes:               c.Bool("yes"),
+			}
+			var err error
+			if profile != "" {
+				err = apps.ScaleToProfile(ctx, currentApp, c.String("profiles-file"), profile, opts)
+			} else {
+				err = apps.Scale(ctx, currentApp, c.Args().Slice(), opts)
+			}
 			if err != nil {
 				errorQuit(ctx, err)
 			}
@@ -53,4 +111,51 @@ var (
 			_ = autocomplete.ScaleAutoComplete(ctx, c)
 		},
 	}
+
+	containerSizesCommand = cli.Command{
+		Name:     "container-sizes",
+		Category: "App Management",
+		Usage:    "List the available container sizes with their prices",
+		Description: CommandDescription{
+			Description: "List the available container sizes with their memory, swap, CPU, and their hourly and 30 days prices",
+			Examples:    []string{"scalingo container-sizes"},
+			SeeAlso:     []string{"scale", "cost"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			err := apps.ContainerSizes(ctx)
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
+
+	costCommand = cli.Command{
+		Name:     "cost",
+		Category: "App Management",
+		Usage:    "Estimate the cost of the containers of your applications",
+		Flags: []cli.Flag{
+			&cli.StringFlag{Name: "project", Usage: "Only consider the apps of a project. The filter uses the format <ownerUsername>/<projectName>"},
+		},
+		Description: CommandDescription{
+			Description: "Estimate the cost for 30 days of the containers of all your applications, from their current formation. The addons are not included.",
+			Examples: []string{
+				"scalingo cost",
+				"scalingo cost --project my-user/my-project",
+			},
+			SeeAlso: []string{"scale", "container-sizes"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			projectSlug := c.String("project")
+			if !isValidProjectSlug(projectSlug) {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "project filter doesn't respect the expected format"), c, "cost")
+			}
+
+			err := apps.Cost(ctx, projectSlug)
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+	}
 )
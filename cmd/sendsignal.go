@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"strings"
 
 	"github.com/urfave/cli/v3"
 
@@ -15,33 +16,51 @@ import (
 var (
 	sendSignalCommand = cli.Command{
 		Name:      "send-signal",
-		Aliases:   []string{"kill"},
 		Category:  "App Management",
 		Usage:     "Send SIGUSR1 or SIGUSR2 to your application containers",
-		ArgsUsage: "container-type...",
+		ArgsUsage: "[signal] container-type...",
 		Flags: []cli.Flag{&appFlag,
-			&cli.StringFlag{Name: "signal", Aliases: []string{"s"}, Usage: "signal to send to the container", Required: true},
+			&cli.StringFlag{Name: "signal", Aliases: []string{"s"}, Usage: "signal to send to the container"},
+			&cli.StringSliceFlag{Name: "type", Aliases: []string{"t"}, Usage: "send the signal to all the containers of this type"},
+			&cli.BoolFlag{Name: "wait", Usage: "follow the containers until they are replaced"},
 		},
 		Description: CommandDescription{
-			Description: "Send SIGUSR1 or SIGUSR2 to your application containers",
+			Description: `Send SIGUSR1 or SIGUSR2 to your application containers
+
+The signal is given with '--signal' or as the first argument. With '--type', the signal is sent to all the containers of the type. With '--wait', the command follows the containers until they are replaced by running containers. With a signal which doesn't stop the process, like SIGUSR1 or SIGUSR2, the containers still running after the signal are not waited for.`,
 			Examples: []string{
 				"scalingo --app my-app send-signal --signal SIGUSR1 web-1",
 				"scalingo --app my-app send-signal --signal SIGUSR2 web-1 web-2",
 				"scalingo --app my-app send-signal --signal SIGUSR2 web",
+				"scalingo --app my-app send-signal --type worker SIGUSR1",
 			},
+			SeeAlso: []string{"kill"},
 		}.Render(),
 		Action: func(ctx context.Context, c *cli.Command) error {
 			currentApp := detect.CurrentApp(ctx, c)
-			if c.Args().Len() == 0 {
+			signal := c.String("signal")
+			args := c.Args().Slice()
+			if signal == "" && len(args) > 0 && strings.HasPrefix(args[0], "SIG") {
+				signal, args = args[0], args[1:]
+			}
+			if len(args) == 0 && len(c.StringSlice("type")) == 0 {
 				err := cli.ShowCommandHelp(ctx, c, "send-signal")
 				if err != nil {
 					return errors.Wrapf(ctx, err, "fail to show command helper")
 				}
 				return nil
 			}
+			if signal == "" {
+				errorQuitWithHelpMessage(ctx, errors.New(ctx, "the signal must be given with --signal or as the first argument"), c, "send-signal")
+			}
 			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
 
-			err := apps.SendSignal(ctx, currentApp, c.String("signal"), c.Args().Slice())
+			err := apps.SendSignal(ctx, currentApp, apps.SendSignalOpts{
+				Signal:     signal,
+				Containers: args,
+				Types:      c.StringSlice("type"),
+				Wait:       c.Bool("wait"),
+			})
 			if err != nil {
 				errorQuit(ctx, err)
 			}
@@ -51,4 +70,50 @@ var (
 			_ = autocomplete.CmdFlagsAutoComplete(c, "send-signal")
 		},
 	}
+
+	killCommand = cli.Command{
+		Name:      "kill",
+		Category:  "App Management",
+		Usage:     "Kill stuck application containers",
+		ArgsUsage: "container...",
+		Flags: []cli.Flag{&appFlag,
+			&cli.StringFlag{Name: "signal", Aliases: []string{"s"}, Value: apps.SignalKill, Usage: "signal to send to the container"},
+			&cli.BoolFlag{Name: "wait", Usage: "follow the containers until they are replaced"},
+		},
+		Description: CommandDescription{
+			Description: `Kill stuck application containers by sending them SIGKILL
+
+Only the containers named exactly are killed, a container type is refused: use 'send-signal --type' to send a signal to all the containers of a type. The killed containers are replaced by the platform. With '--wait', the command follows the containers until they are replaced by running containers, or gone for the one-off containers.`,
+			Examples: []string{
+				"scalingo --app my-app kill web-1",
+				"scalingo --app my-app kill --wait worker-1 worker-2",
+			},
+			SeeAlso: []string{"send-signal", "ps"},
+		}.Render(),
+		Action: func(ctx context.Context, c *cli.Command) error {
+			currentApp := detect.CurrentApp(ctx, c)
+			if c.Args().Len() == 0 {
+				err := cli.ShowCommandHelp(ctx, c, "kill")
+				if err != nil {
+					return errors.Wrapf(ctx, err, "fail to show command helper")
+				}
+				return nil
+			}
+			utils.CheckForConsent(ctx, currentApp, utils.ConsentTypeContainers)
+
+			err := apps.SendSignal(ctx, currentApp, apps.SendSignalOpts{
+				Signal:      c.String("signal"),
+				Containers:  c.Args().Slice(),
+				Wait:        c.Bool("wait"),
+				ExactLabels: true,
+			})
+			if err != nil {
+				errorQuit(ctx, err)
+			}
+			return nil
+		},
+		ShellComplete: func(_ context.Context, c *cli.Command) {
+			_ = autocomplete.CmdFlagsAutoComplete(c, "kill")
+		},
+	}
 )
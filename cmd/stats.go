@@ -21,6 +21,7 @@ var (
 		Description: CommandDescription{
 			Description: "Display metrics of your application running containers",
 			Examples:    []string{"scalingo --app my-app stats"},
+			SeeAlso:     []string{"top", "metrics"},
 		}.Render(),
 
 		Action: func(ctx context.Context, c *cli.Command) error {
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/Scalingo/cli/detect"
+	"github.com/Scalingo/cli/top"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+var topCommand = cli.Command{
+	Name:      "top",
+	Category:  "Display metrics of the running containers",
+	Usage:     "Display a live dashboard of the containers of one or several applications",
+	ArgsUsage: "[app...]",
+	Flags: []cli.Flag{
+		&appFlag,
+		&cli.DurationFlag{Name: "interval", Aliases: []string{"n"}, Value: top.DefaultInterval, Usage: "Refresh interval of the dashboard"},
+	},
+	Description: CommandDescription{
+		Description: `Display a full screen dashboard of the containers of one or several applications
+
+The dashboard shows the CPU, memory and swap usage of the containers, the requests per minute and the 5XX errors of the router, and the status of the last deployment of each application.
+
+Keys: 'c', 'm', 's' and 'n' sort the containers by CPU, memory, swap or name, 'r' reverses the order, space refreshes the dashboard and 'q' quits.`,
+		Examples: []string{
+			"scalingo --app my-app top",
+			"scalingo top my-app my-worker-app --interval 10s",
+		},
+		SeeAlso: []string{"stats", "metrics", "ps"},
+	}.Render(),
+	Action: func(ctx context.Context, c *cli.Command) error {
+		apps := c.Args().Slice()
+		if len(apps) == 0 {
+			apps = []string{detect.CurrentApp(ctx, c)}
+		}
+		if c.Duration("interval") < top.MinInterval {
+			errorQuitWithHelpMessage(ctx, errors.Newf(ctx, "the refresh interval must be at least %s", top.MinInterval), c, "top")
+		}
+
+		err := top.Run(ctx, apps, top.Opts{Interval: c.Duration("interval")})
+		if err != nil {
+			errorQuit(ctx, err)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// listAddonsConcurrency is the maximal number of apps whose addons are listed
+// simultaneously
+const listAddonsConcurrency = 5
+
+// DatabaseAddon is a database addon of one of the apps of the user
+type DatabaseAddon struct {
+	App   *scalingo.App
+	Addon *scalingo.Addon
+}
+
+// IsDatabaseAddon returns true if the addon provides one of the supported
+// databases.
+func IsDatabaseAddon(addon *scalingo.Addon) bool {
+	if addon.AddonProvider == nil {
+		return false
+	}
+	return slices.ContainsFunc(SupportedDatabases, func(s string) bool {
+		return strings.EqualFold(s, addon.AddonProvider.ID)
+	})
+}
+
+// ListDatabaseAddons returns the database addons of all the apps of the user,
+// sorted by app. If projectSlug is set, only the apps of this project are
+// considered.
+func ListDatabaseAddons(ctx context.Context, c *scalingo.Client, projectSlug string) ([]DatabaseAddon, error) {
+	appsList, err := c.AppsList(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "list apps")
+	}
+	appsList = apps.FilterByProject(appsList, projectSlug)
+
+	addonsByApp := make([][]DatabaseAddon, len(appsList))
+	errs := make([]error, len(appsList))
+	semaphore := make(chan struct{}, listAddonsConcurrency)
+	wg := &sync.WaitGroup{}
+	for i, app := range appsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			addons, err := c.AddonsList(ctx, app.Name)
+			if err != nil {
+				errs[i] = errors.Wrapf(ctx, err, "list addons of %s", app.Name)
+				return
+			}
+			for _, addon := range addons {
+				if IsDatabaseAddon(addon) {
+					addonsByApp[i] = append(addonsByApp[i], DatabaseAddon{App: app, Addon: addon})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var databaseAddons []DatabaseAddon
+	for i := range appsList {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		databaseAddons = append(databaseAddons, addonsByApp[i]...)
+	}
+	slices.SortStableFunc(databaseAddons, func(a, b DatabaseAddon) int {
+		return strings.Compare(a.App.Name, b.App.Name)
+	})
+	return databaseAddons, nil
+}
+
+// ForEachDatabaseAddon calls fn for each database addon, concurrently. At most
+// listAddonsConcurrency calls are running simultaneously. The index of the
+// addon is given to fn so that results can be stored without locking.
+func ForEachDatabaseAddon(databaseAddons []DatabaseAddon, fn func(i int, databaseAddon DatabaseAddon)) {
+	semaphore := make(chan struct{}, listAddonsConcurrency)
+	wg := &sync.WaitGroup{}
+	for i, databaseAddon := range databaseAddons {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			fn(i, databaseAddon)
+		}()
+	}
+	wg.Wait()
+}
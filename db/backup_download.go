@@ -10,6 +10,8 @@ import (
 
 	"github.com/briandowns/spinner"
 	"github.com/cheggaaa/pb/v3"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/go-scalingo/v11"
@@ -26,6 +28,11 @@ const (
 type DownloadBackupOpts struct {
 	Output string
 	Silent bool
+	// Parallel is the number of chunks of the backup downloaded simultaneously
+	Parallel int
+	// VerifyOnly verifies the already downloaded archive of the backup instead
+	// of downloading it
+	VerifyOnly bool
 }
 
 func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts DownloadBackupOpts) error {
@@ -45,6 +52,9 @@ func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts Dow
 	if opts.Silent {
 		logWriter = io.Discard
 	}
+	if writeToStdout && (opts.Parallel > 1 || opts.VerifyOnly) {
+		return errors.New(ctx, "the backup must be written to a file to be downloaded in parallel or verified")
+	}
 
 	client, err := config.ScalingoClient(ctx)
 	if err != nil {
@@ -88,27 +98,73 @@ func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts Dow
 		return errors.Wrap(ctx, err, "get backup")
 	}
 
-	// Generate the filename and file writer
-	filepath := ""
-	if !writeToStdout { // No need to generate the filename nor the file writer if we're outputing to stdout
-		filepath = backup.Name + ".tar.gz" // Default filename
-		if opts.Output != "" {             // If the Output flag was defined
-			if isDir(opts.Output) { // If it's a directory use the default filename in this directory
-				filepath = fmt.Sprintf("%s/%s.tar.gz", opts.Output, backup.Name)
-			} else { // If the output is not a directory use it as the filename
-				filepath = opts.Output
-			}
-		}
-		// Open the output file
-		f, err := os.OpenFile(filepath, os.O_RDWR|os.O_CREATE, 0644)
+	if writeToStdout {
+		spinner.Stop()
+		return streamBackup(ctx, client, app, addonID, backup, fileWriter, logWriter)
+	}
+
+	filepath := backupFilePath(backup, opts.Output)
+	if opts.VerifyOnly {
+		spinner.Stop()
+		files, err := verifyBackupArchive(ctx, filepath, int64(backup.Size))
 		if err != nil {
-			return errors.Wrap(ctx, err, "fail to open file")
+			return errors.Wrapf(ctx, err, "verify %s", filepath)
+		}
+		_, _ = fmt.Fprintf(logWriter, "-----> %s is a valid archive of backup %s\n", filepath, backup.ID)
+		renderArchiveFiles(files)
+		return nil
+	}
+
+	spinner.Stop()
+	files, err := downloadBackupArchive(ctx, client, app, addonID, backup, filepath, opts.Parallel, logWriter)
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(logWriter, "-----> Archive verified, it contains %d files\n", len(files))
+	_, _ = fmt.Fprintf(logWriter, "===> %s\n", filepath)
+	return nil
+}
+
+// downloadBackupArchive downloads a backup into path and verifies the
+// downloaded archive. An interrupted download into the same path is resumed.
+// The download is skipped if a valid archive of the backup is already at
+// path.
+func downloadBackupArchive(ctx context.Context, client *scalingo.Client, app, addonID string, backup *scalingo.Backup, path string, parallel int, logWriter io.Writer) ([]archiveFile, error) {
+	stat, err := os.Stat(path)
+	if err == nil && backup.Size > 0 && stat.Size() == int64(backup.Size) {
+		files, err := verifyBackupArchive(ctx, path, int64(backup.Size))
+		if err == nil {
+			_, _ = fmt.Fprintf(logWriter, "-----> %s is already downloaded\n", path)
+			return files, nil
 		}
-		fileWriter = f // Set the output file as the fileWriter
 	}
 
 	// Get the pre-signed download URL
-	downloadURL, err := client.BackupDownloadURL(ctx, app, addonID, backupID)
+	downloadURL, err := client.BackupDownloadURL(ctx, app, addonID, backup.ID)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "get backup download URL")
+	}
+	debug.Println("Temporary URL to download backup is: ", downloadURL)
+
+	err = downloadFile(ctx, downloadURL, path, int64(backup.Size), transferOpts{
+		Parallel: parallel,
+		Progress: logWriter,
+	})
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "download file")
+	}
+
+	files, err := verifyBackupArchive(ctx, path, int64(backup.Size))
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "verify %s, remove it and download the backup again", path)
+	}
+	return files, nil
+}
+
+// streamBackup writes the backup to w. The download can't be resumed.
+func streamBackup(ctx context.Context, client *scalingo.Client, app, addonID string, backup *scalingo.Backup, w, logWriter io.Writer) error {
+	// Get the pre-signed download URL
+	downloadURL, err := client.BackupDownloadURL(ctx, app, addonID, backup.ID)
 	if err != nil {
 		return errors.Wrap(ctx, err, "get backup download URL")
 	}
@@ -121,9 +177,6 @@ func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts Dow
 	}
 	defer resp.Body.Close()
 
-	// Stop the spinner
-	spinner.Stop()
-
 	if resp.StatusCode != http.StatusOK {
 		return httpclient.NewRequestFailedError(ctx, resp, &httpclient.APIRequest{
 			URL:    downloadURL,
@@ -137,15 +190,8 @@ func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts Dow
 		SetWriter(logWriter)
 	bar.Start()
 	reader := bar.NewProxyReader(resp.Body) // Did I tell you this library is awesome?
-	_, err = io.Copy(fileWriter, reader)
-	if writeToStdout { // If we were writing the file to Stdout do not print the filepath at the end
-		bar.Finish()
-	} else {
-		// If we were writing the backup to a file, write the file path
-		bar.Finish()
-		_, _ = fmt.Fprintf(logWriter, "===> %s\n", filepath)
-	}
-
+	_, err = io.Copy(w, reader)
+	bar.Finish()
 	if err != nil {
 		return errors.Wrap(ctx, err, "download file")
 	}
@@ -153,6 +199,30 @@ func DownloadBackup(ctx context.Context, app, addonID, backupID string, opts Dow
 	return nil
 }
 
+// backupFilePath returns the path of the archive of the backup from the
+// output option: the default filename in the current directory, the default
+// filename in the output directory, or the output file.
+func backupFilePath(backup *scalingo.Backup, output string) string {
+	filepath := backup.Name + ".tar.gz" // Default filename
+	if output != "" {                   // If the Output flag was defined
+		if isDir(output) { // If it's a directory use the default filename in this directory
+			filepath = fmt.Sprintf("%s/%s.tar.gz", output, backup.Name)
+		} else { // If the output is not a directory use it as the filename
+			filepath = output
+		}
+	}
+	return filepath
+}
+
+func renderArchiveFiles(files []archiveFile) {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"File", "Size"})
+	for _, file := range files {
+		_ = t.Append([]string{file.Name, humanize.IBytes(uint64(file.Size))})
+	}
+	_ = t.Render()
+}
+
 // isDir returns true if it's a valid path to a directory, false otherwise
 func isDir(path string) bool {
 	a, err := os.Open(path)
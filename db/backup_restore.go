@@ -0,0 +1,247 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/cli/config"
+	cliio "github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	// maxUploadSize is the maximal size of a file uploaded to a one-off container
+	maxUploadSize = 100 * 1024 * 1024
+
+	restoreArchivePath = "/tmp/backup.tar.gz"
+	restoreDir         = "/tmp/restore"
+)
+
+type RestoreBackupOpts struct {
+	// App and AddonID are the app and the addon owning the backup
+	App     string
+	AddonID string
+	// BackupID is the backup to restore, defaults to the most recent successful
+	// one
+	BackupID string
+	// File is a local backup archive to restore instead of a backup of the
+	// addon
+	File string
+	// TargetApp is the app whose database is restored, defaults to App
+	TargetApp string
+	// Confirm skips the interactive confirmation if it is the name of the
+	// target app
+	Confirm string
+	Size    string
+}
+
+type restoreScriptOpts struct {
+	providerID string
+	// archive is the path of the backup archive in the one-off container. If
+	// empty, the archive is downloaded from $BACKUP_URL.
+	archive      string
+	expectedSize uint64
+	checksum     string
+}
+
+func RestoreBackup(ctx context.Context, opts RestoreBackupOpts) error {
+	if opts.TargetApp == "" {
+		opts.TargetApp = opts.App
+	}
+	if opts.File != "" && opts.BackupID != "" {
+		return errors.New(ctx, "a backup ID and a local file can't be both restored")
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	addon, err := c.AddonShow(ctx, opts.App, opts.AddonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get addon information")
+	}
+	providerID, engine, err := addonEngine(ctx, addon, postgreSQLProviderID, mySQLProviderID, mongoDBProviderID, redisProviderID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "check addon")
+	}
+
+	targetAddonID, err := utils.GetAddonUUIDFromType(ctx, opts.TargetApp, providerID)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "find %s addon on app %s", engine.Name, opts.TargetApp)
+	}
+	targetURL, _, _, err := dbURL(ctx, opts.TargetApp, engine.VariableName, engine.URLSchemes)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "resolve %s URL of app %s", engine.Name, opts.TargetApp)
+	}
+	if targetURL.Scheme == "rediss" {
+		return errors.New(ctx, "Redis backups can't be restored when TLS connections are enforced")
+	}
+
+	env := connectionEnv(targetDBEnvPrefix, targetURL)
+	scriptOpts := restoreScriptOpts{providerID: providerID}
+	var files []string
+	source := ""
+	if opts.File != "" {
+		checksum, size, err := fileChecksum(ctx, opts.File)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "compute checksum of %s", opts.File)
+		}
+		if size > maxUploadSize {
+			return errors.Newf(ctx, "%s is too large to be uploaded (%s, maximum is %s)", opts.File, humanize.IBytes(uint64(size)), humanize.IBytes(maxUploadSize))
+		}
+		files = append(files, opts.File)
+		scriptOpts.archive = oneOffUploadsDir + "/" + filepath.Base(opts.File)
+		scriptOpts.expectedSize = uint64(size)
+		scriptOpts.checksum = checksum
+		source = fmt.Sprintf("%s (SHA256 %s)", opts.File, checksum)
+	} else {
+		if opts.BackupID == "" {
+			backups, err := c.BackupList(ctx, opts.App, opts.AddonID)
+			if err != nil {
+				return errors.Wrap(ctx, err, "list backups")
+			}
+			opts.BackupID, err = getLastSuccessfulBackup(ctx, backups)
+			if err != nil {
+				return errors.Wrap(ctx, err, "get a successful backup")
+			}
+		}
+		backup, err := c.BackupShow(ctx, opts.App, opts.AddonID, opts.BackupID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get backup")
+		}
+		downloadURL, err := c.BackupDownloadURL(ctx, opts.App, opts.AddonID, opts.BackupID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get backup download URL")
+		}
+		env = append(env, "BACKUP_URL="+downloadURL)
+		scriptOpts.expectedSize = backup.Size
+		source = fmt.Sprintf("the backup %s of %s (%s, %s)", backup.Name, opts.App, backup.CreatedAt.Format(utils.TimeFormat), humanize.Bytes(backup.Size))
+	}
+
+	cliio.Statusf("Restoring %s into the %s database %s of %s\n", source, engine.Name, targetAddonID, cliio.Bold(opts.TargetApp))
+	if opts.Confirm != opts.TargetApp {
+		err := utils.ConfirmByTyping(ctx,
+			fmt.Sprintf("/!\\ All the data of the %s database of %s will be replaced, this operation is irreversible.", engine.Name, opts.TargetApp),
+			opts.TargetApp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	script, err := restoreScript(ctx, scriptOpts)
+	if err != nil {
+		return errors.Wrap(ctx, err, "build restore script")
+	}
+
+	err = apps.Run(ctx, apps.RunOpts{
+		DisplayCmd: "backups-restore " + opts.TargetApp,
+		App:        opts.TargetApp,
+		Cmd:        []string{"dbclient-fetcher", engine.Fetcher, "&&", "set -o pipefail", "&&", script},
+		CmdEnv:     env,
+		Files:      files,
+		Size:       opts.Size,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "run restore in a one-off container")
+	}
+	return nil
+}
+
+// restoreScript returns the shell commands executed in the one-off container
+// to verify the backup archive and restore it into the target database.
+func restoreScript(ctx context.Context, opts restoreScriptOpts) (string, error) {
+	var commands []string
+	archive := opts.archive
+	if archive == "" {
+		archive = restoreArchivePath
+	}
+	quotedArchive := shellQuote(archive)
+	if opts.archive == "" {
+		commands = append(commands,
+			step("Downloading backup"),
+			`curl --fail --show-error --progress-bar --output `+quotedArchive+` "$BACKUP_URL"`,
+		)
+	}
+
+	commands = append(commands, step("Verifying backup archive"))
+	if opts.expectedSize > 0 {
+		commands = append(commands, fmt.Sprintf(
+			`{ test "$(stat -c %%s %s)" -eq %d || { echo 'Invalid archive size, expected %d bytes' >&2; exit 1; }; }`,
+			quotedArchive, opts.expectedSize, opts.expectedSize,
+		))
+	}
+	if opts.checksum != "" {
+		commands = append(commands, fmt.Sprintf(`echo %s | sha256sum --check --strict -`, shellQuote(opts.checksum+"  "+archive)))
+	} else {
+		commands = append(commands, `sha256sum `+quotedArchive)
+	}
+	commands = append(commands, `gzip --test `+quotedArchive, step("Restoring backup"))
+
+	switch opts.providerID {
+	case postgreSQLProviderID:
+		commands = append(commands, `tar --extract --gzip --to-stdout --file `+quotedArchive+` | `+pgRestoreCommand)
+	case mySQLProviderID:
+		commands = append(commands, `tar --extract --gzip --to-stdout --file `+quotedArchive+` | `+mySQLTargetCommand)
+	case mongoDBProviderID:
+		commands = append(commands, `mkdir -p `+restoreDir+` && tar --extract --gzip --file `+quotedArchive+` --directory `+restoreDir)
+		commands = append(commands, mongoDumpDirCommands(restoreDir)...)
+		commands = append(commands, mongoRestoreCommand+` --dir="$SOURCE_DUMP_DIR"`)
+	case redisProviderID:
+		// The AOF file contains the commands to replay, in the Redis protocol
+		commands = append(commands,
+			`mkdir -p `+restoreDir+` && tar --extract --gzip --file `+quotedArchive+` --directory `+restoreDir,
+			`export AOF_FILE=$(find `+restoreDir+` -name '*.aof' | head -n 1)`,
+			`{ test -n "$AOF_FILE" || { echo 'No AOF file in the archive, it cannot be restored from a one-off container' >&2; exit 1; }; }`,
+			`redis-cli -u "$TARGET_DB_URL" FLUSHALL`,
+			`redis-cli -u "$TARGET_DB_URL" --pipe < "$AOF_FILE"`,
+		)
+	default:
+		return "", errors.Newf(ctx, "restoring %s backups is not supported", opts.providerID)
+	}
+	commands = append(commands, step("Backup restored"))
+
+	return strings.Join(commands, " && "), nil
+}
+
+func step(message string) string {
+	return "echo " + shellQuote("-----> "+message)
+}
+
+// fileChecksum returns the SHA256 checksum and the size of a local file,
+// displaying a progress bar while reading it.
+func fileChecksum(ctx context.Context, path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, errors.Wrap(ctx, err, "open file")
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return "", 0, errors.Wrap(ctx, err, "stat file")
+	}
+
+	bar := pb.New64(stat.Size()).Set(pb.Bytes, true).SetWriter(os.Stderr)
+	bar.Start()
+	hash := sha256.New()
+	_, err = io.Copy(hash, bar.NewProxyReader(f))
+	bar.Finish()
+	if err != nil {
+		return "", 0, errors.Wrap(ctx, err, "read file")
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), stat.Size(), nil
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreScript(t *testing.T) {
+	tests := map[string]struct {
+		opts             restoreScriptOpts
+		expectedCommands []string
+		expectedError    string
+	}{
+		"PostgreSQL backup": {
+			opts: restoreScriptOpts{providerID: postgreSQLProviderID, expectedSize: 1024},
+			expectedCommands: []string{
+				`curl --fail --show-error --progress-bar --output '/tmp/backup.tar.gz' "$BACKUP_URL"`,
+				`test "$(stat -c %s '/tmp/backup.tar.gz')" -eq 1024`,
+				`sha256sum '/tmp/backup.tar.gz' && gzip --test '/tmp/backup.tar.gz'`,
+				`tar --extract --gzip --to-stdout --file '/tmp/backup.tar.gz' | pg_restore --clean`,
+			},
+		},
+		"MySQL local file": {
+			opts: restoreScriptOpts{providerID: mySQLProviderID, archive: "/tmp/uploads/backup.tar.gz", expectedSize: 10, checksum: "abc"},
+			expectedCommands: []string{
+				`echo 'abc  /tmp/uploads/backup.tar.gz' | sha256sum --check --strict -`,
+				`tar --extract --gzip --to-stdout --file '/tmp/uploads/backup.tar.gz' | mysql -h "$TARGET_DB_HOST"`,
+			},
+		},
+		"MongoDB backup": {
+			opts: restoreScriptOpts{providerID: mongoDBProviderID},
+			expectedCommands: []string{
+				`tar --extract --gzip --file '/tmp/backup.tar.gz' --directory /tmp/restore`,
+				`mongorestore --drop --uri="$TARGET_DB_URL" --nsFrom="$SOURCE_DB_NAME.*" --nsTo="$TARGET_DB_NAME.*" --dir="$SOURCE_DUMP_DIR"`,
+			},
+		},
+		"Redis backup": {
+			opts: restoreScriptOpts{providerID: redisProviderID},
+			expectedCommands: []string{
+				`redis-cli -u "$TARGET_DB_URL" FLUSHALL && redis-cli -u "$TARGET_DB_URL" --pipe < "$AOF_FILE"`,
+			},
+		},
+		"unsupported engine": {
+			opts:          restoreScriptOpts{providerID: "elasticsearch"},
+			expectedError: "restoring elasticsearch backups is not supported",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := restoreScript(context.Background(), test.opts)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, command := range test.expectedCommands {
+				assert.Contains(t, script, command)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	humanize "github.com/dustin/go-humanize"
+
+	httpclient "github.com/Scalingo/go-scalingo/v11/http"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	defaultDownloadChunkSize = 64 * 1024 * 1024
+	downloadMaxAttempts      = 5
+	partialFileSuffix        = ".part"
+	chunksStateFileSuffix    = ".part.chunks"
+)
+
+type transferOpts struct {
+	// Parallel is the number of chunks downloaded simultaneously. The file is
+	// downloaded as a single stream if it is lower than 2.
+	Parallel int
+	// Progress is where the progress bar and the messages are written
+	Progress  io.Writer
+	chunkSize int64
+}
+
+// chunksState is persisted next to the partial file during a parallel
+// download to resume it
+type chunksState struct {
+	Size      int64 `json:"size"`
+	ChunkSize int64 `json:"chunk_size"`
+	Done      []int `json:"done"`
+}
+
+// downloadFile downloads the file at url into path. The data are written in a
+// partial file renamed once the download is complete. If the partial file of
+// a previous download exists, the download is resumed with HTTP Range
+// requests. Interrupted transfers are retried.
+func downloadFile(ctx context.Context, url, path string, size int64, opts transferOpts) error {
+	if opts.chunkSize == 0 {
+		opts.chunkSize = defaultDownloadChunkSize
+	}
+	partialPath := path + partialFileSuffix
+	statePath := path + chunksStateFileSuffix
+
+	chunked := opts.Parallel > 1 && size > opts.chunkSize
+	if !chunked {
+		// The partial file of an interrupted parallel download is allocated to
+		// the size of the file, it can't be resumed as a single stream
+		_, err := os.Stat(statePath)
+		if err == nil {
+			state := readChunksState(statePath)
+			if state.Size == size && state.ChunkSize > 0 {
+				chunked = true
+				opts.chunkSize = state.ChunkSize
+				opts.Parallel = 1
+			} else {
+				err = removeFiles(partialPath, statePath)
+				if err != nil {
+					return errors.Wrap(ctx, err, "remove the files of the previous download")
+				}
+			}
+		}
+	}
+
+	bar := pb.New64(size).Set(pb.Bytes, true).SetWriter(opts.Progress)
+	var err error
+	if chunked {
+		err = downloadChunks(ctx, url, partialPath, statePath, size, opts, bar)
+	} else {
+		err = downloadStream(ctx, url, partialPath, size, opts, bar)
+	}
+	bar.Finish()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(partialPath, path)
+	if err != nil {
+		return errors.Wrap(ctx, err, "rename partial file")
+	}
+	return nil
+}
+
+func downloadStream(ctx context.Context, url, partialPath string, size int64, opts transferOpts, bar *pb.ProgressBar) error {
+	f, err := os.OpenFile(partialPath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(ctx, err, "open partial file")
+	}
+	defer f.Close()
+
+	offset, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return errors.Wrap(ctx, err, "seek end of partial file")
+	}
+	if size > 0 && offset > size {
+		offset, err = restartDownload(ctx, f)
+		if err != nil {
+			return err
+		}
+	}
+	if offset > 0 {
+		fmt.Fprintf(opts.Progress, "-----> Resuming the download at %s\n", humanize.IBytes(uint64(offset)))
+	}
+	bar.SetCurrent(offset)
+	bar.Start()
+
+	var lastErr error
+	for attempt := 0; attempt < downloadMaxAttempts; attempt++ {
+		if size > 0 && offset == size {
+			return nil
+		}
+		if attempt > 0 {
+			fmt.Fprintf(opts.Progress, "-----> Download interrupted (%v), resuming at %s\n", lastErr, humanize.IBytes(uint64(offset)))
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+
+		res, err := rangeRequest(ctx, url, offset, -1)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if res.StatusCode == http.StatusOK && offset > 0 {
+			// The server ignored the Range header and sends the whole file
+			offset, err = restartDownload(ctx, f)
+			if err != nil {
+				res.Body.Close()
+				return err
+			}
+			bar.SetCurrent(0)
+		}
+
+		n, err := io.Copy(f, bar.NewProxyReader(res.Body))
+		res.Body.Close()
+		offset += n
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if size <= 0 || offset == size {
+			return nil
+		}
+		lastErr = errors.Newf(ctx, "received %d of %d bytes", offset, size)
+	}
+	return errors.Wrapf(ctx, lastErr, "download failed after %d attempts", downloadMaxAttempts)
+}
+
+func restartDownload(ctx context.Context, f *os.File) (int64, error) {
+	err := f.Truncate(0)
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "truncate partial file")
+	}
+	_, err = f.Seek(0, io.SeekStart)
+	if err != nil {
+		return 0, errors.Wrap(ctx, err, "seek start of partial file")
+	}
+	return 0, nil
+}
+
+// downloadChunks downloads the file by chunks, opts.Parallel chunks at a
+// time. The downloaded chunks are saved in a state file to only download the
+// missing chunks when resuming.
+func downloadChunks(ctx context.Context, url, partialPath, statePath string, size int64, opts transferOpts, bar *pb.ProgressBar) error {
+	state := readChunksState(statePath)
+	if state.Size != size || state.ChunkSize != opts.chunkSize {
+		state = chunksState{Size: size, ChunkSize: opts.chunkSize}
+		// Chunks of a previous download can't be reused
+		err := os.Remove(partialPath)
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(ctx, err, "remove partial file")
+		}
+	}
+
+	f, err := os.OpenFile(partialPath, os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return errors.Wrap(ctx, err, "open partial file")
+	}
+	defer f.Close()
+	err = f.Truncate(size)
+	if err != nil {
+		return errors.Wrap(ctx, err, "allocate partial file")
+	}
+
+	chunksCount := int((size + opts.chunkSize - 1) / opts.chunkSize)
+	chunkBoundaries := func(chunk int) (int64, int64) {
+		start := int64(chunk) * opts.chunkSize
+		return start, min(start+opts.chunkSize, size) - 1
+	}
+
+	var done int64
+	pending := make(chan int, chunksCount)
+	for chunk := 0; chunk < chunksCount; chunk++ {
+		if slices.Contains(state.Done, chunk) {
+			start, end := chunkBoundaries(chunk)
+			done += end - start + 1
+			continue
+		}
+		pending <- chunk
+	}
+	close(pending)
+	if done > 0 {
+		fmt.Fprintf(opts.Progress, "-----> Resuming the download, %s already downloaded\n", humanize.IBytes(uint64(done)))
+	}
+	bar.SetCurrent(done)
+	bar.Start()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stateLock := &sync.Mutex{}
+	errs := make(chan error, opts.Parallel)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < opts.Parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range pending {
+				if ctx.Err() != nil {
+					return
+				}
+				start, end := chunkBoundaries(chunk)
+				err := downloadChunk(ctx, url, f, start, end, bar)
+				if err != nil {
+					errs <- errors.Wrapf(ctx, err, "download chunk %d", chunk)
+					cancel()
+					return
+				}
+
+				stateLock.Lock()
+				state.Done = append(state.Done, chunk)
+				err = writeChunksState(statePath, state)
+				stateLock.Unlock()
+				if err != nil {
+					errs <- errors.Wrap(ctx, err, "save download state")
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	err = <-errs
+	if err != nil {
+		return err
+	}
+
+	_ = os.Remove(statePath)
+	return nil
+}
+
+func downloadChunk(ctx context.Context, url string, f *os.File, start, end int64, bar *pb.ProgressBar) error {
+	length := end - start + 1
+	var lastErr error
+	for attempt := 0; attempt < downloadMaxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+		res, err := rangeRequest(ctx, url, start, end)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if res.StatusCode != http.StatusPartialContent {
+			res.Body.Close()
+			return errors.New(ctx, "the server does not support range requests, download the backup without --parallel")
+		}
+
+		n, err := io.Copy(io.NewOffsetWriter(f, start), bar.NewProxyReader(io.LimitReader(res.Body, length)))
+		res.Body.Close()
+		if err == nil && n == length {
+			return nil
+		}
+		// The chunk is downloaded again from its start
+		bar.Add64(-n)
+		lastErr = err
+		if lastErr == nil {
+			lastErr = errors.Newf(ctx, "received %d of %d bytes", n, length)
+		}
+	}
+	return errors.Wrapf(ctx, lastErr, "failed after %d attempts", downloadMaxAttempts)
+}
+
+// rangeRequest requests the bytes of the file from start to end (included).
+// The end is open if it is negative.
+func rangeRequest(ctx context.Context, url string, start, end int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create request")
+	}
+	if start > 0 || end >= 0 {
+		byteRange := "bytes=" + strconv.FormatInt(start, 10) + "-"
+		if end >= 0 {
+			byteRange += strconv.FormatInt(end, 10)
+		}
+		req.Header.Set("Range", byteRange)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "send request")
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusPartialContent {
+		err := httpclient.NewRequestFailedError(ctx, res, &httpclient.APIRequest{URL: url, Method: http.MethodGet})
+		res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+func removeFiles(paths ...string) error {
+	for _, path := range paths {
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func readChunksState(path string) chunksState {
+	var state chunksState
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	_ = json.Unmarshal(content, &state)
+	return state
+}
+
+func writeChunksState(path string, state chunksState) error {
+	content, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
@@ -0,0 +1,155 @@
+package db
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	buffer := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+func TestDownloadFile(t *testing.T) {
+	// Random data are not compressed, the archive is made of several chunks
+	data := make([]byte, 8000)
+	rand.New(rand.NewSource(1)).Read(data)
+	archive := buildArchive(t, map[string]string{"dump.pgsql": string(data)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "backup.tar.gz", time.Time{}, bytes.NewReader(archive))
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		parallel int
+		// partial is the content of the partial file of a previous download
+		partial []byte
+		// chunksState is the state of a previous parallel download
+		chunksState *chunksState
+	}{
+		"single stream": {},
+		"resume a single stream": {
+			partial: archive[:len(archive)/2],
+		},
+		"resume with a corrupted partial file": {
+			partial: append(bytes.Clone(archive), 'x'),
+		},
+		"parallel chunks": {
+			parallel: 3,
+		},
+		"resume parallel chunks": {
+			parallel:    3,
+			partial:     append(bytes.Clone(archive[:1024]), make([]byte, len(archive)-1024)...),
+			chunksState: &chunksState{Size: int64(len(archive)), ChunkSize: 1024, Done: []int{0}},
+		},
+		"resume parallel chunks without parallelism": {
+			partial:     append(bytes.Clone(archive[:1024]), make([]byte, len(archive)-1024)...),
+			chunksState: &chunksState{Size: int64(len(archive)), ChunkSize: 1024, Done: []int{0}},
+		},
+		"stale parallel chunks without parallelism": {
+			partial:     make([]byte, len(archive)),
+			chunksState: &chunksState{Size: int64(len(archive)) + 1, ChunkSize: 1024, Done: []int{0}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "backup.tar.gz")
+			if test.partial != nil {
+				require.NoError(t, os.WriteFile(path+partialFileSuffix, test.partial, 0644))
+			}
+			if test.chunksState != nil {
+				require.NoError(t, writeChunksState(path+chunksStateFileSuffix, *test.chunksState))
+			}
+
+			err := downloadFile(ctx, server.URL, path, int64(len(archive)), transferOpts{
+				Parallel: test.parallel, Progress: io.Discard, chunkSize: 1024,
+			})
+			require.NoError(t, err)
+
+			content, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, archive, content)
+			assert.NoFileExists(t, path+partialFileSuffix)
+			assert.NoFileExists(t, path+chunksStateFileSuffix)
+
+			files, err := verifyBackupArchive(ctx, path, int64(len(archive)))
+			require.NoError(t, err)
+			assert.Equal(t, []archiveFile{{Name: "dump.pgsql", Size: 8000}}, files)
+		})
+	}
+}
+
+func TestVerifyBackupArchive(t *testing.T) {
+	archive := buildArchive(t, map[string]string{"dump.sql": "CREATE TABLE users();"})
+	corrupted := bytes.Clone(archive)
+	corrupted[len(corrupted)-10] ^= 0xff
+
+	tests := map[string]struct {
+		content       []byte
+		expectedSize  int64
+		expectedError string
+	}{
+		"valid archive": {
+			content:      archive,
+			expectedSize: int64(len(archive)),
+		},
+		"size mismatch": {
+			content:       archive[:len(archive)-1],
+			expectedSize:  int64(len(archive)),
+			expectedError: "bytes expected",
+		},
+		"truncated archive": {
+			content:       archive[:len(archive)/2],
+			expectedError: "unexpected EOF",
+		},
+		"corrupted archive": {
+			content:       corrupted,
+			expectedError: "invalid",
+		},
+		"not an archive": {
+			content:       []byte("not a gzip file"),
+			expectedError: "invalid gzip archive",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "backup.tar.gz")
+			require.NoError(t, os.WriteFile(path, test.content, 0644))
+
+			files, err := verifyBackupArchive(context.Background(), path, test.expectedSize)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, []archiveFile{{Name: "dump.sql", Size: 21}}, files)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// archiveFile is a file contained in a backup archive
+type archiveFile struct {
+	Name string
+	Size int64
+}
+
+// verifyBackupArchive checks the size of a backup archive and reads it
+// entirely to check its gzip and tar structures. It returns the files of the
+// archive. The size is not checked if expectedSize is 0.
+func verifyBackupArchive(ctx context.Context, path string, expectedSize int64) ([]archiveFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "open archive")
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "stat archive")
+	}
+	if expectedSize > 0 && stat.Size() != expectedSize {
+		return nil, errors.Newf(ctx, "the archive size is %d bytes, %d bytes expected", stat.Size(), expectedSize)
+	}
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid gzip archive")
+	}
+	defer gzipReader.Close()
+
+	var files []archiveFile
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "invalid tar archive")
+		}
+		n, err := io.Copy(io.Discard, tarReader)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "read %s from the archive", header.Name)
+		}
+		files = append(files, archiveFile{Name: header.Name, Size: n})
+	}
+	// Reading the end of the gzip stream verifies its checksum
+	_, err = io.Copy(io.Discard, gzipReader)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid gzip archive")
+	}
+
+	if len(files) == 0 {
+		return nil, errors.New(ctx, "the archive is empty")
+	}
+	return files, nil
+}
@@ -0,0 +1,186 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+type BackupsReportOpts struct {
+	ProjectSlug string
+	// MaxAge is the maximal age of the last successful backup of a database
+	MaxAge time.Duration
+	// FailuresPeriod is the period during which failed backups are reported
+	FailuresPeriod time.Duration
+	JSON           bool
+}
+
+// backupsReportEntry is the backup health of a database addon
+type backupsReportEntry struct {
+	App                        string     `json:"app"`
+	AddonID                    string     `json:"addon_id"`
+	DatabaseType               string     `json:"database_type"`
+	LastSuccessfulBackupAt     *time.Time `json:"last_successful_backup_at"`
+	RecentFailedBackups        int        `json:"recent_failed_backups"`
+	PeriodicBackupsEnabled     bool       `json:"periodic_backups_enabled"`
+	PeriodicBackupsScheduledAt []int      `json:"periodic_backups_scheduled_at"`
+	Issues                     []string   `json:"issues"`
+	Healthy                    bool       `json:"healthy"`
+}
+
+// BackupsReport checks the backups of all the database addons of the user. It
+// returns an error if a database has a backup issue.
+func BackupsReport(ctx context.Context, opts BackupsReportOpts) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	databaseAddons, err := ListDatabaseAddons(ctx, c, opts.ProjectSlug)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list database addons")
+	}
+
+	now := time.Now()
+	entries := make([]backupsReportEntry, len(databaseAddons))
+	ForEachDatabaseAddon(databaseAddons, func(i int, databaseAddon DatabaseAddon) {
+		app, addonID := databaseAddon.App.Name, databaseAddon.Addon.ID
+		entry := backupsReportEntry{
+			App:          app,
+			AddonID:      addonID,
+			DatabaseType: databaseAddon.Addon.AddonProvider.Name,
+		}
+
+		database, err := c.DatabaseShow(ctx, app, addonID)
+		if err != nil {
+			entry.Issues = []string{fmt.Sprintf("cannot get the database: %v", err)}
+			entries[i] = entry
+			return
+		}
+		entry.PeriodicBackupsEnabled = database.PeriodicBackupsEnabled
+		entry.PeriodicBackupsScheduledAt = database.PeriodicBackupsScheduledAt
+
+		backups, err := c.BackupList(ctx, app, addonID)
+		if err != nil {
+			entry.Issues = []string{fmt.Sprintf("cannot list the backups: %v", err)}
+			entries[i] = entry
+			return
+		}
+		entries[i] = checkBackups(entry, database, backups, now, opts)
+	})
+
+	unhealthy := 0
+	for i := range entries {
+		entries[i].Healthy = len(entries[i].Issues) == 0
+		if !entries[i].Healthy {
+			unhealthy++
+		}
+	}
+	// The databases with issues are displayed first
+	slices.SortStableFunc(entries, func(a, b backupsReportEntry) int {
+		return len(b.Issues) - len(a.Issues)
+	})
+
+	if opts.JSON {
+		err := json.NewEncoder(os.Stdout).Encode(entries)
+		if err != nil {
+			return errors.Wrap(ctx, err, "encode report")
+		}
+	} else {
+		renderBackupsReport(entries, now)
+	}
+
+	if unhealthy > 0 {
+		return errors.Newf(ctx, "%d of %d databases have backup issues", unhealthy, len(entries))
+	}
+	if !opts.JSON {
+		io.Statusf("The backups of the %d databases are healthy\n", len(entries))
+	}
+	return nil
+}
+
+// checkBackups fills the report entry of a database from its periodic backups
+// configuration and its backups.
+func checkBackups(entry backupsReportEntry, database scalingo.Database, backups []scalingo.Backup, now time.Time, opts BackupsReportOpts) backupsReportEntry {
+	failuresSince := now.Add(-opts.FailuresPeriod)
+	for _, backup := range backups {
+		switch backup.Status {
+		case scalingo.BackupStatusDone:
+			if entry.LastSuccessfulBackupAt == nil || backup.CreatedAt.After(*entry.LastSuccessfulBackupAt) {
+				createdAt := backup.CreatedAt
+				entry.LastSuccessfulBackupAt = &createdAt
+			}
+		case scalingo.BackupStatusError:
+			if backup.CreatedAt.After(failuresSince) {
+				entry.RecentFailedBackups++
+			}
+		}
+	}
+
+	if entry.LastSuccessfulBackupAt == nil {
+		entry.Issues = append(entry.Issues, "no successful backup")
+	} else if age := now.Sub(*entry.LastSuccessfulBackupAt); age > opts.MaxAge {
+		entry.Issues = append(entry.Issues, fmt.Sprintf("last successful backup is %s old", formatDuration(age)))
+	}
+	if entry.RecentFailedBackups > 0 {
+		entry.Issues = append(entry.Issues, fmt.Sprintf("%d failed backups in the last %s", entry.RecentFailedBackups, formatDuration(opts.FailuresPeriod)))
+	}
+
+	if !database.PeriodicBackupsEnabled {
+		entry.Issues = append(entry.Issues, "periodic backups are disabled")
+	} else if len(database.PeriodicBackupsScheduledAt) == 0 {
+		entry.Issues = append(entry.Issues, "periodic backups are enabled without schedule")
+	} else if slices.ContainsFunc(database.PeriodicBackupsScheduledAt, func(hour int) bool { return hour < 0 || hour > 23 }) {
+		entry.Issues = append(entry.Issues, fmt.Sprintf("periodic backups have an invalid schedule %v", database.PeriodicBackupsScheduledAt))
+	}
+
+	return entry
+}
+
+func renderBackupsReport(entries []backupsReportEntry, now time.Time) {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"App", "Addon", "Last Successful Backup", "Periodic Backups", "Issues"})
+	for _, entry := range entries {
+		lastBackup := "never"
+		if entry.LastSuccessfulBackupAt != nil {
+			lastBackup = humanize.RelTime(*entry.LastSuccessfulBackupAt, now, "ago", "from now")
+		}
+		periodicBackups := "disabled"
+		if entry.PeriodicBackupsEnabled && len(entry.PeriodicBackupsScheduledAt) > 0 {
+			periodicBackups = "daily at " + formatScheduledAt(entry.PeriodicBackupsScheduledAt)
+		}
+		issues := io.Green("none")
+		if len(entry.Issues) > 0 {
+			issues = io.BoldRed(strings.Join(entry.Issues, "\n"))
+		}
+
+		_ = t.Append([]string{
+			entry.App,
+			fmt.Sprintf("%s (%s)", entry.DatabaseType, entry.AddonID),
+			lastBackup,
+			periodicBackups,
+			issues,
+		})
+	}
+	_ = t.Render()
+}
+
+// formatDuration formats durations in days or hours, e.g. 3d or 5h
+func formatDuration(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
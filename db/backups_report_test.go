@@ -0,0 +1,57 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestCheckBackups(t *testing.T) {
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	opts := BackupsReportOpts{MaxAge: 26 * time.Hour, FailuresPeriod: 7 * 24 * time.Hour}
+	enabledDatabase := scalingo.Database{PeriodicBackupsEnabled: true, PeriodicBackupsScheduledAt: []int{2}}
+
+	tests := map[string]struct {
+		database       scalingo.Database
+		backups        []scalingo.Backup
+		expectedIssues []string
+	}{
+		"healthy database": {
+			database: enabledDatabase,
+			backups: []scalingo.Backup{
+				{Status: scalingo.BackupStatusDone, CreatedAt: now.Add(-10 * time.Hour)},
+				{Status: scalingo.BackupStatusError, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+			},
+		},
+		"outdated backup and recent failures": {
+			database: enabledDatabase,
+			backups: []scalingo.Backup{
+				{Status: scalingo.BackupStatusError, CreatedAt: now.Add(-10 * time.Hour)},
+				{Status: scalingo.BackupStatusError, CreatedAt: now.Add(-34 * time.Hour)},
+				{Status: scalingo.BackupStatusDone, CreatedAt: now.Add(-58 * time.Hour)},
+			},
+			expectedIssues: []string{"last successful backup is 2d old", "2 failed backups in the last 7d"},
+		},
+		"periodic backups disabled without backup": {
+			database:       scalingo.Database{},
+			expectedIssues: []string{"no successful backup", "periodic backups are disabled"},
+		},
+		"periodic backups without schedule": {
+			database: scalingo.Database{PeriodicBackupsEnabled: true},
+			backups: []scalingo.Backup{
+				{Status: scalingo.BackupStatusDone, CreatedAt: now.Add(-time.Hour)},
+			},
+			expectedIssues: []string{"periodic backups are enabled without schedule"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			entry := checkBackups(backupsReportEntry{}, test.database, test.backups, now, opts)
+			assert.Equal(t, test.expectedIssues, entry.Issues)
+		})
+	}
+}
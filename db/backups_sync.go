@@ -0,0 +1,300 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/s3"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	backupsManifestPath = "manifest.json"
+	backupArchiveSuffix = ".tar.gz"
+	// backupArchiveTimeFormat is the format of the creation date prefixing the
+	// archive names, which sorts the archives chronologically
+	backupArchiveTimeFormat = "20060102T150405Z"
+)
+
+type BackupsSyncOpts struct {
+	// App and AddonID select the addon to sync if All is false
+	App     string
+	AddonID string
+	// All syncs all the database addons of the user, or of ProjectSlug
+	All         bool
+	ProjectSlug string
+	// Dest is a local directory or an S3 URL (s3://bucket/prefix)
+	Dest string
+	// Retain is the number of backups kept per addon, all the backups are
+	// kept if it is 0
+	Retain   int
+	Parallel int
+}
+
+// backupsManifest describes the backups present in the destination
+type backupsManifest struct {
+	UpdatedAt time.Time              `json:"updated_at"`
+	Backups   []backupsManifestEntry `json:"backups"`
+}
+
+type backupsManifestEntry struct {
+	App          string    `json:"app"`
+	AddonID      string    `json:"addon_id"`
+	DatabaseType string    `json:"database_type"`
+	BackupID     string    `json:"backup_id"`
+	Name         string    `json:"name"`
+	Method       string    `json:"method"`
+	CreatedAt    time.Time `json:"created_at"`
+	Size         uint64    `json:"size"`
+	Path         string    `json:"path"`
+	Files        []string  `json:"files"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
+
+// BackupsSync copies the successful backups of database addons which are not
+// yet in the destination, deletes the backups exceeding the retention and
+// writes a manifest of the synchronized backups. The backups of an addon are
+// stored in the <app>/<addon ID> directory of the destination.
+func BackupsSync(ctx context.Context, opts BackupsSyncOpts) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	store, err := newBackupsStore(ctx, opts.Dest, s3.ConfigFromEnv())
+	if err != nil {
+		return err
+	}
+
+	var databaseAddons []DatabaseAddon
+	if opts.All {
+		databaseAddons, err = ListDatabaseAddons(ctx, c, opts.ProjectSlug)
+		if err != nil {
+			return errors.Wrap(ctx, err, "list database addons")
+		}
+	} else {
+		addon, err := c.AddonShow(ctx, opts.App, opts.AddonID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get addon information")
+		}
+		databaseAddons = []DatabaseAddon{{App: &scalingo.App{Name: opts.App}, Addon: &addon}}
+	}
+	databaseAddons = slices.DeleteFunc(databaseAddons, func(databaseAddon DatabaseAddon) bool {
+		provider := databaseAddon.Addon.AddonProvider
+		return provider == nil || provider.ID == elasticsearchProviderID || provider.ID == openSearchProviderID
+	})
+	if len(databaseAddons) == 0 {
+		return errors.New(ctx, "no database addon with backups found")
+	}
+
+	manifest, err := readBackupsManifest(ctx, store)
+	if err != nil {
+		return err
+	}
+
+	failures := 0
+	for _, databaseAddon := range databaseAddons {
+		err := syncAddonBackups(ctx, c, store, databaseAddon, &manifest, opts)
+		if err != nil {
+			io.Errorf("Fail to sync the backups of %s (%s): %v\n", databaseAddon.App.Name, databaseAddon.Addon.ID, err)
+			failures++
+		}
+	}
+
+	manifest.UpdatedAt = time.Now()
+	err = writeBackupsManifest(ctx, store, manifest)
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return errors.Newf(ctx, "the backups of %d of %d addons failed to sync", failures, len(databaseAddons))
+	}
+	io.Statusf("The backups of %d addons are synchronized to %s\n", len(databaseAddons), opts.Dest)
+	return nil
+}
+
+func syncAddonBackups(ctx context.Context, c *scalingo.Client, store backupsStore, databaseAddon DatabaseAddon, manifest *backupsManifest, opts BackupsSyncOpts) error {
+	app, addon := databaseAddon.App.Name, databaseAddon.Addon
+	dir := path.Join(app, addon.ID)
+
+	backups, err := c.BackupList(ctx, app, addon.ID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list backups")
+	}
+	archives, err := store.List(ctx, dir)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list synchronized backups")
+	}
+
+	newBackups := backupsToSync(backups, archives, opts.Retain)
+	if len(newBackups) == 0 {
+		io.Statusf("The backups of %s (%s) are up to date\n", app, addon.AddonProvider.Name)
+	}
+	for _, backup := range newBackups {
+		name := backupArchiveName(backup)
+		archivePath := path.Join(dir, name)
+		localPath := store.LocalPath(archivePath)
+		err := os.MkdirAll(filepath.Dir(localPath), 0755)
+		if err != nil {
+			return errors.Wrap(ctx, err, "create directory")
+		}
+
+		io.Statusf("Downloading backup %s of %s (%s)\n", backup.Name, app, humanize.IBytes(backup.Size))
+		files, err := downloadBackupArchive(ctx, c, app, addon.ID, &backup, localPath, opts.Parallel, os.Stdout)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "download backup %s", backup.ID)
+		}
+		err = store.Store(ctx, localPath, archivePath)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "store backup %s", backup.ID)
+		}
+
+		entry := backupsManifestEntry{
+			App:          app,
+			AddonID:      addon.ID,
+			DatabaseType: addon.AddonProvider.Name,
+			BackupID:     backup.ID,
+			Name:         backup.Name,
+			Method:       string(backup.Method),
+			CreatedAt:    backup.CreatedAt,
+			Size:         backup.Size,
+			Path:         archivePath,
+			SyncedAt:     time.Now(),
+		}
+		for _, file := range files {
+			entry.Files = append(entry.Files, file.Name)
+		}
+		manifest.Backups = append(slices.DeleteFunc(manifest.Backups, func(e backupsManifestEntry) bool {
+			return e.Path == archivePath
+		}), entry)
+		archives = append(archives, name)
+	}
+
+	for _, name := range expiredArchives(archives, opts.Retain) {
+		archivePath := path.Join(dir, name)
+		io.Infof("Deleting %s, older than the %d most recent backups\n", archivePath, opts.Retain)
+		err := store.Delete(ctx, archivePath)
+		if err != nil {
+			return errors.Wrap(ctx, err, "apply retention")
+		}
+		manifest.Backups = slices.DeleteFunc(manifest.Backups, func(e backupsManifestEntry) bool {
+			return e.Path == archivePath
+		})
+	}
+	return nil
+}
+
+// backupsToSync returns the successful backups missing from the archives,
+// most recent first. Only the retain most recent backups are returned, as
+// the older ones would be deleted by the retention.
+func backupsToSync(backups []scalingo.Backup, archives []string, retain int) []scalingo.Backup {
+	var successfulBackups []scalingo.Backup
+	for _, backup := range backups {
+		if backup.Status == scalingo.BackupStatusDone {
+			successfulBackups = append(successfulBackups, backup)
+		}
+	}
+	slices.SortFunc(successfulBackups, func(a, b scalingo.Backup) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	if retain > 0 && len(successfulBackups) > retain {
+		successfulBackups = successfulBackups[:retain]
+	}
+
+	present := map[string]bool{}
+	for _, archive := range archives {
+		if backupID, ok := parseBackupArchiveName(archive); ok {
+			present[backupID] = true
+		}
+	}
+	return slices.DeleteFunc(successfulBackups, func(backup scalingo.Backup) bool {
+		return present[backup.ID]
+	})
+}
+
+// expiredArchives returns the archives older than the retain most recent
+// ones. The files which are not backup archives are ignored.
+func expiredArchives(archives []string, retain int) []string {
+	if retain <= 0 {
+		return nil
+	}
+	var backupArchives []string
+	for _, archive := range archives {
+		if _, ok := parseBackupArchiveName(archive); ok {
+			backupArchives = append(backupArchives, archive)
+		}
+	}
+	if len(backupArchives) <= retain {
+		return nil
+	}
+	slices.Sort(backupArchives)
+	slices.Reverse(backupArchives)
+	return backupArchives[retain:]
+}
+
+// backupArchiveName returns the name of the archive of a backup in the
+// destination: <creation date>_<backup ID>.tar.gz
+func backupArchiveName(backup scalingo.Backup) string {
+	return backup.CreatedAt.UTC().Format(backupArchiveTimeFormat) + "_" + backup.ID + backupArchiveSuffix
+}
+
+// parseBackupArchiveName returns the backup ID of an archive name built by
+// backupArchiveName
+func parseBackupArchiveName(name string) (string, bool) {
+	name, ok := strings.CutSuffix(name, backupArchiveSuffix)
+	if !ok {
+		return "", false
+	}
+	createdAt, backupID, ok := strings.Cut(name, "_")
+	if !ok || backupID == "" {
+		return "", false
+	}
+	_, err := time.Parse(backupArchiveTimeFormat, createdAt)
+	if err != nil {
+		return "", false
+	}
+	return backupID, true
+}
+
+func readBackupsManifest(ctx context.Context, store backupsStore) (backupsManifest, error) {
+	var manifest backupsManifest
+	content, err := store.ReadFile(ctx, backupsManifestPath)
+	if err != nil {
+		return manifest, errors.Wrap(ctx, err, "read manifest")
+	}
+	if content == nil {
+		return manifest, nil
+	}
+	err = json.Unmarshal(content, &manifest)
+	if err != nil {
+		return manifest, errors.Wrap(ctx, err, "invalid manifest")
+	}
+	return manifest, nil
+}
+
+func writeBackupsManifest(ctx context.Context, store backupsStore, manifest backupsManifest) error {
+	slices.SortFunc(manifest.Backups, func(a, b backupsManifestEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(ctx, err, "encode manifest")
+	}
+	err = store.WriteFile(ctx, backupsManifestPath, content)
+	if err != nil {
+		return errors.Wrap(ctx, err, "write manifest")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	stderrors "errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Scalingo/cli/s3"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// backupsStore is the destination of the backups synchronization. Paths are
+// slash-separated and relative to the root of the destination.
+type backupsStore interface {
+	// List returns the names of the files in the directory dir
+	List(ctx context.Context, dir string) ([]string, error)
+	// LocalPath returns the local path where the file stored at path is
+	// downloaded before being stored
+	LocalPath(path string) string
+	// Store stores the downloaded file at path
+	Store(ctx context.Context, localPath, path string) error
+	Delete(ctx context.Context, path string) error
+	// ReadFile returns nil if the file does not exist
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+	WriteFile(ctx context.Context, path string, content []byte) error
+}
+
+// newBackupsStore returns the store of the destination, a local directory or
+// an S3 URL (s3://bucket/prefix).
+func newBackupsStore(ctx context.Context, dest string, s3Config s3.Config) (backupsStore, error) {
+	location, ok := strings.CutPrefix(dest, "s3://")
+	if !ok {
+		return localBackupsStore{root: dest}, nil
+	}
+
+	bucket, prefix, _ := strings.Cut(location, "/")
+	if bucket == "" {
+		return nil, errors.Newf(ctx, "invalid S3 destination %s, the format is s3://bucket/prefix", dest)
+	}
+	if s3Config.AccessKeyID == "" || s3Config.SecretAccessKey == "" {
+		return nil, errors.New(ctx, "the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables are required to sync to S3")
+	}
+	return s3BackupsStore{
+		client:  s3.New(s3Config),
+		bucket:  bucket,
+		prefix:  strings.Trim(prefix, "/"),
+		workDir: filepath.Join(os.TempDir(), "scalingo-backups-sync"),
+	}, nil
+}
+
+type localBackupsStore struct {
+	root string
+}
+
+func (s localBackupsStore) List(ctx context.Context, dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.root, filepath.FromSlash(dir)))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "list %s", dir)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s localBackupsStore) LocalPath(path string) string {
+	return filepath.Join(s.root, filepath.FromSlash(path))
+}
+
+func (s localBackupsStore) Store(_ context.Context, _, _ string) error {
+	// The backups are directly downloaded at their final location
+	return nil
+}
+
+func (s localBackupsStore) Delete(ctx context.Context, path string) error {
+	err := os.Remove(s.LocalPath(path))
+	if err != nil {
+		return errors.Wrapf(ctx, err, "delete %s", path)
+	}
+	return nil
+}
+
+func (s localBackupsStore) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	content, err := os.ReadFile(s.LocalPath(path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read %s", path)
+	}
+	return content, nil
+}
+
+func (s localBackupsStore) WriteFile(ctx context.Context, path string, content []byte) error {
+	err := os.MkdirAll(filepath.Dir(s.LocalPath(path)), 0755)
+	if err != nil {
+		return errors.Wrap(ctx, err, "create directory")
+	}
+	err = os.WriteFile(s.LocalPath(path), content, 0644)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "write %s", path)
+	}
+	return nil
+}
+
+type s3BackupsStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+	// workDir is the local directory where the backups are downloaded
+	// before being uploaded
+	workDir string
+}
+
+func (s s3BackupsStore) key(p string) string {
+	return strings.TrimPrefix(path.Join(s.prefix, p), "/")
+}
+
+func (s s3BackupsStore) List(ctx context.Context, dir string) ([]string, error) {
+	prefix := s.key(dir) + "/"
+	objects, err := s.client.ListObjects(ctx, s.bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Key, prefix)
+		// Only the files of the directory are listed, not the ones of its
+		// subdirectories
+		if !strings.Contains(name, "/") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (s s3BackupsStore) LocalPath(path string) string {
+	return filepath.Join(s.workDir, filepath.FromSlash(path))
+}
+
+func (s s3BackupsStore) Store(ctx context.Context, localPath, path string) error {
+	err := s.client.UploadFile(ctx, s.bucket, s.key(path), localPath)
+	if err != nil {
+		return err
+	}
+	_ = os.Remove(localPath)
+	return nil
+}
+
+func (s s3BackupsStore) Delete(ctx context.Context, path string) error {
+	return s.client.DeleteObject(ctx, s.bucket, s.key(path))
+}
+
+func (s s3BackupsStore) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	content, err := s.client.GetObject(ctx, s.bucket, s.key(path))
+	if stderrors.Is(err, s3.ErrNotFound) {
+		return nil, nil
+	}
+	return content, err
+}
+
+func (s s3BackupsStore) WriteFile(ctx context.Context, path string, content []byte) error {
+	return s.client.PutObject(ctx, s.bucket, s.key(path), content)
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestBackupsToSync(t *testing.T) {
+	now := time.Date(2026, 1, 15, 2, 0, 0, 0, time.UTC)
+	backups := []scalingo.Backup{
+		{ID: "b4", Status: scalingo.BackupStatusDone, CreatedAt: now},
+		{ID: "b3", Status: scalingo.BackupStatusError, CreatedAt: now.Add(-24 * time.Hour)},
+		{ID: "b1", Status: scalingo.BackupStatusDone, CreatedAt: now.Add(-72 * time.Hour)},
+		{ID: "b2", Status: scalingo.BackupStatusDone, CreatedAt: now.Add(-48 * time.Hour)},
+	}
+
+	tests := map[string]struct {
+		archives    []string
+		retain      int
+		expectedIDs []string
+	}{
+		"empty destination": {
+			expectedIDs: []string{"b4", "b2", "b1"},
+		},
+		"backups already present": {
+			archives:    []string{"20260113T020000Z_b2.tar.gz", "20260115T020000Z_b4.tar.gz.part", "manifest.json"},
+			expectedIDs: []string{"b4", "b1"},
+		},
+		"backups beyond the retention are not synced": {
+			archives:    []string{"20260115T020000Z_b4.tar.gz"},
+			retain:      2,
+			expectedIDs: []string{"b2"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var ids []string
+			for _, backup := range backupsToSync(backups, test.archives, test.retain) {
+				ids = append(ids, backup.ID)
+			}
+			assert.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestExpiredArchives(t *testing.T) {
+	archives := []string{
+		"20260113T020000Z_b2.tar.gz",
+		"20260115T020000Z_b4.tar.gz",
+		"notes.txt",
+		"20260112T020000Z_b1.tar.gz",
+		"20260114T020000Z_b3.tar.gz.part",
+	}
+
+	assert.Equal(t, []string{"20260113T020000Z_b2.tar.gz", "20260112T020000Z_b1.tar.gz"}, expiredArchives(archives, 1))
+	assert.Empty(t, expiredArchives(archives, 3))
+	assert.Empty(t, expiredArchives(archives, 0))
+	assert.Equal(t, "20260115T020000Z_b4.tar.gz", backupArchiveName(scalingo.Backup{ID: "b4", CreatedAt: time.Date(2026, 1, 15, 3, 0, 0, 0, time.FixedZone("CET", 3600))}))
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+type CloneOpts struct {
+	FromApp string
+	ToApp   string
+	// Addon is the type (e.g. postgresql) or the ID of the addon of the source
+	// app. The addon of the same type is used on the target app.
+	Addon string
+	// Live makes the clone dump the source database instead of restoring its
+	// last backup
+	Live bool
+	// BackupID is the backup of the source database to restore. Defaults to the
+	// most recent successful one.
+	BackupID string
+	// ExcludeTables are tables (or MongoDB collections) whose data are not
+	// copied
+	ExcludeTables []string
+	// AnonymizeScript is a local script executed on the target database once
+	// the clone is done
+	AnonymizeScript string
+	// Confirm skips the interactive confirmation if it is the name of the
+	// target app
+	Confirm string
+	Size    string
+}
+
+type cloneScriptOpts struct {
+	providerID      string
+	live            bool
+	excludeTables   []string
+	anonymizeScript string
+}
+
+func Clone(ctx context.Context, opts CloneOpts) error {
+	if opts.FromApp == opts.ToApp {
+		return errors.New(ctx, "the source and the target apps must be different")
+	}
+	if opts.Live && opts.BackupID != "" {
+		return errors.New(ctx, "a backup can't be selected when cloning a live database")
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	sourceAddonID, err := utils.GetAddonUUIDFromType(ctx, opts.FromApp, opts.Addon)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "find addon on app %s", opts.FromApp)
+	}
+	sourceAddon, err := c.AddonShow(ctx, opts.FromApp, sourceAddonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get source addon information")
+	}
+	providerID, engine, err := addonEngine(ctx, sourceAddon, postgreSQLProviderID, mySQLProviderID, mongoDBProviderID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "check source addon")
+	}
+	if providerID == mySQLProviderID && !opts.Live && len(opts.ExcludeTables) > 0 {
+		return errors.New(ctx, "excluding tables of a MySQL database is only possible when cloning a live database")
+	}
+
+	targetAddonID, err := utils.GetAddonUUIDFromType(ctx, opts.ToApp, providerID)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "find %s addon on app %s", engine.Name, opts.ToApp)
+	}
+	targetURL, _, _, err := dbURL(ctx, opts.ToApp, engine.VariableName, engine.URLSchemes)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "resolve %s URL of app %s", engine.Name, opts.ToApp)
+	}
+
+	env := connectionEnv(targetDBEnvPrefix, targetURL)
+	source := ""
+	if opts.Live {
+		sourceURL, _, _, err := dbURL(ctx, opts.FromApp, engine.VariableName, engine.URLSchemes)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "resolve %s URL of app %s", engine.Name, opts.FromApp)
+		}
+		env = append(env, connectionEnv(sourceDBEnvPrefix, sourceURL)...)
+		source = fmt.Sprintf("the live database of %s", opts.FromApp)
+	} else {
+		if opts.BackupID == "" {
+			backups, err := c.BackupList(ctx, opts.FromApp, sourceAddonID)
+			if err != nil {
+				return errors.Wrap(ctx, err, "list backups")
+			}
+			opts.BackupID, err = getLastSuccessfulBackup(ctx, backups)
+			if err != nil {
+				return errors.Wrap(ctx, err, "get a successful backup")
+			}
+		}
+		backup, err := c.BackupShow(ctx, opts.FromApp, sourceAddonID, opts.BackupID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get backup")
+		}
+		downloadURL, err := c.BackupDownloadURL(ctx, opts.FromApp, sourceAddonID, opts.BackupID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get backup download URL")
+		}
+		env = append(env, "BACKUP_URL="+downloadURL)
+		source = fmt.Sprintf("the backup %s of %s (%s)", backup.Name, opts.FromApp, backup.CreatedAt.Format(utils.TimeFormat))
+	}
+
+	io.Statusf("Cloning %s into the %s database %s of %s\n", source, engine.Name, targetAddonID, io.Bold(opts.ToApp))
+	if opts.Confirm != opts.ToApp {
+		err := utils.ConfirmByTyping(ctx,
+			fmt.Sprintf("/!\\ All the data of the %s database of %s will be replaced, this operation is irreversible.", engine.Name, opts.ToApp),
+			opts.ToApp,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	var files []string
+	if opts.AnonymizeScript != "" {
+		files = append(files, opts.AnonymizeScript)
+	}
+	script, err := cloneScript(ctx, cloneScriptOpts{
+		providerID:      providerID,
+		live:            opts.Live,
+		excludeTables:   opts.ExcludeTables,
+		anonymizeScript: opts.AnonymizeScript,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "build clone script")
+	}
+
+	err = apps.Run(ctx, apps.RunOpts{
+		DisplayCmd: fmt.Sprintf("db-clone %s -> %s", opts.FromApp, opts.ToApp),
+		App:        opts.ToApp,
+		Cmd:        []string{"dbclient-fetcher", engine.Fetcher, "&&", "set -o pipefail", "&&", script},
+		CmdEnv:     env,
+		Files:      files,
+		Size:       opts.Size,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "run clone in a one-off container")
+	}
+	return nil
+}
+
+// cloneScript returns the shell commands executed in the one-off container of
+// the target app to copy the data. The connection information of the
+// databases and the backup URL are given through the environment.
+func cloneScript(ctx context.Context, opts cloneScriptOpts) (string, error) {
+	var commands []string
+	switch opts.providerID {
+	case postgreSQLProviderID:
+		restore := pgRestoreCommand
+		if opts.live {
+			dump := `pg_dump --format=custom --no-owner --no-acl`
+			for _, table := range opts.excludeTables {
+				dump += " --exclude-table-data=" + shellQuote(table)
+			}
+			commands = append(commands, dump+` "$SOURCE_DB_URL" | `+restore)
+		} else if len(opts.excludeTables) == 0 {
+			commands = append(commands, `curl --fail --silent --show-error "$BACKUP_URL" | tar --extract --gzip --to-stdout | `+restore)
+		} else {
+			patterns := make([]string, 0, len(opts.excludeTables))
+			for _, table := range opts.excludeTables {
+				patterns = append(patterns, "-e "+shellQuote(" TABLE DATA [^ ]+ "+table+" "))
+			}
+			commands = append(commands,
+				`curl --fail --silent --show-error "$BACKUP_URL" | tar --extract --gzip --to-stdout > /tmp/clone.pgdump`,
+				`pg_restore --list /tmp/clone.pgdump | grep -v -E `+strings.Join(patterns, " ")+` > /tmp/clone.list`,
+				restore+` --use-list /tmp/clone.list /tmp/clone.pgdump`,
+			)
+		}
+	case mySQLProviderID:
+		restore := mySQLTargetCommand
+		if opts.live {
+			dump := `mysqldump --single-transaction --routines --triggers -h "$SOURCE_DB_HOST" -P "$SOURCE_DB_PORT" -u "$SOURCE_DB_USER" --password="$SOURCE_DB_PASSWORD"`
+			for _, table := range opts.excludeTables {
+				dump += ` --ignore-table="$SOURCE_DB_NAME".` + shellQuote(table)
+			}
+			commands = append(commands, dump+` "$SOURCE_DB_NAME" | `+restore)
+		} else {
+			commands = append(commands, `curl --fail --silent --show-error "$BACKUP_URL" | tar --extract --gzip --to-stdout | `+restore)
+		}
+	case mongoDBProviderID:
+		restore := mongoRestoreCommand
+		for _, collection := range opts.excludeTables {
+			restore += ` --nsExclude="$SOURCE_DB_NAME".` + shellQuote(collection)
+		}
+		if opts.live {
+			commands = append(commands, `mongodump --uri="$SOURCE_DB_URL" --archive | `+restore+` --archive`)
+		} else {
+			commands = append(commands,
+				`mkdir -p /tmp/clone && curl --fail --silent --show-error "$BACKUP_URL" | tar --extract --gzip --directory /tmp/clone`,
+			)
+			commands = append(commands, mongoDumpDirCommands("/tmp/clone")...)
+			commands = append(commands, restore+` --dir="$SOURCE_DUMP_DIR"`)
+		}
+	default:
+		return "", errors.Newf(ctx, "cloning %s databases is not supported", opts.providerID)
+	}
+
+	if opts.anonymizeScript != "" {
+		uploaded := shellQuote(oneOffUploadsDir + "/" + filepath.Base(opts.anonymizeScript))
+		switch opts.providerID {
+		case postgreSQLProviderID:
+			commands = append(commands, `psql --set ON_ERROR_STOP=1 "$TARGET_DB_URL" --file `+uploaded)
+		case mySQLProviderID:
+			commands = append(commands, mySQLTargetCommand+` < `+uploaded)
+		case mongoDBProviderID:
+			commands = append(commands, `mongo "$TARGET_DB_URL" `+uploaded)
+		}
+	}
+
+	return strings.Join(commands, " && "), nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneScript(t *testing.T) {
+	tests := map[string]struct {
+		opts             cloneScriptOpts
+		expectedCommands []string
+		expectedError    string
+	}{
+		"PostgreSQL backup": {
+			opts: cloneScriptOpts{providerID: postgreSQLProviderID},
+			expectedCommands: []string{
+				`curl --fail --silent --show-error "$BACKUP_URL" | tar --extract --gzip --to-stdout | pg_restore --clean --if-exists --no-owner --no-acl --dbname "$TARGET_DB_URL"`,
+			},
+		},
+		"PostgreSQL backup with excluded tables": {
+			opts: cloneScriptOpts{providerID: postgreSQLProviderID, excludeTables: []string{"events"}},
+			expectedCommands: []string{
+				`pg_restore --list /tmp/clone.pgdump | grep -v -E -e ' TABLE DATA [^ ]+ events ' > /tmp/clone.list`,
+				`--use-list /tmp/clone.list /tmp/clone.pgdump`,
+			},
+		},
+		"PostgreSQL live with excluded tables and anonymization": {
+			opts: cloneScriptOpts{providerID: postgreSQLProviderID, live: true, excludeTables: []string{"events", "it's"}, anonymizeScript: "scripts/anonymize.sql"},
+			expectedCommands: []string{
+				`pg_dump --format=custom --no-owner --no-acl --exclude-table-data='events' --exclude-table-data='it'\''s' "$SOURCE_DB_URL" | pg_restore`,
+				`psql --set ON_ERROR_STOP=1 "$TARGET_DB_URL" --file '/tmp/uploads/anonymize.sql'`,
+			},
+		},
+		"MySQL live with excluded tables": {
+			opts: cloneScriptOpts{providerID: mySQLProviderID, live: true, excludeTables: []string{"events"}},
+			expectedCommands: []string{
+				`--ignore-table="$SOURCE_DB_NAME".'events' "$SOURCE_DB_NAME" | mysql -h "$TARGET_DB_HOST"`,
+			},
+		},
+		"MongoDB live": {
+			opts: cloneScriptOpts{providerID: mongoDBProviderID, live: true},
+			expectedCommands: []string{
+				`mongodump --uri="$SOURCE_DB_URL" --archive | mongorestore --drop --uri="$TARGET_DB_URL" --nsFrom="$SOURCE_DB_NAME.*" --nsTo="$TARGET_DB_NAME.*" --archive`,
+			},
+		},
+		"unsupported engine": {
+			opts:          cloneScriptOpts{providerID: redisProviderID},
+			expectedError: "cloning redis databases is not supported",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := cloneScript(context.Background(), test.opts)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, command := range test.expectedCommands {
+				assert.Contains(t, script, command)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	ConsoleFormatTable = "table"
+	ConsoleFormatCSV   = "csv"
+	ConsoleFormatTSV   = "tsv"
+	ConsoleFormatJSON  = "json"
+)
+
+// ConsoleQueryOpts are the options to execute a query or a script with the
+// client of a database console instead of opening an interactive shell.
+type ConsoleQueryOpts struct {
+	// Command is the query (or the command) to execute
+	Command string
+	// File is a local script uploaded to the one-off container and executed by
+	// the client
+	File string
+	// Format of the output, the formats available depend on the database. The
+	// default is the usual output of the client.
+	Format string
+}
+
+func (q ConsoleQueryOpts) interactive() bool {
+	return q.Command == "" && q.File == ""
+}
+
+// validate checks the options are consistent and the format is one of the
+// formats supported by the client of the database.
+func (q ConsoleQueryOpts) validate(ctx context.Context, formats ...string) error {
+	if q.Command != "" && q.File != "" {
+		return errors.New(ctx, "a command and a file can't be both executed")
+	}
+	if q.Format == "" {
+		return nil
+	}
+	if q.interactive() {
+		return errors.New(ctx, "the output format can only be set when executing a command or a file")
+	}
+	if !slices.Contains(formats, q.Format) {
+		return errors.Newf(ctx, "invalid format '%s', must be one of: %s", q.Format, strings.Join(formats, ", "))
+	}
+	return nil
+}
+
+// fetchClient returns the command installing the client of the database
+// in the one-off container, chained with the client command. When a query is
+// executed, the output of the installation is discarded not to be mixed
+// with the output of the client.
+func (q ConsoleQueryOpts) fetchClient(client string) []string {
+	if q.interactive() {
+		return []string{"dbclient-fetcher", client, "&&"}
+	}
+	return []string{"dbclient-fetcher", client, ">/dev/null", "&&"}
+}
+
+// uploadedFile returns the quoted path of the script once uploaded in the
+// one-off container.
+func (q ConsoleQueryOpts) uploadedFile() string {
+	return shellQuote(oneOffUploadsDir + "/" + filepath.Base(q.File))
+}
+
+// runConsole starts the console in a one-off container. When a query is
+// executed, only the output of the client is written on stdout, and the CLI
+// exits with the status of the client.
+func runConsole(ctx context.Context, runOpts apps.RunOpts, q ConsoleQueryOpts) error {
+	if !q.interactive() {
+		runOpts.Silent = true
+		if q.File != "" {
+			runOpts.Files = []string{q.File}
+		}
+	}
+	return apps.Run(ctx, runOpts)
+}
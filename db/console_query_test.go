@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleQueryOpts_validate(t *testing.T) {
+	tests := map[string]struct {
+		query         ConsoleQueryOpts
+		expectedError string
+	}{
+		"interactive console": {},
+		"command with a supported format": {
+			query: ConsoleQueryOpts{Command: "SELECT 1", Format: ConsoleFormatCSV},
+		},
+		"command and file": {
+			query:         ConsoleQueryOpts{Command: "SELECT 1", File: "script.sql"},
+			expectedError: "a command and a file can't be both executed",
+		},
+		"format without query": {
+			query:         ConsoleQueryOpts{Format: ConsoleFormatCSV},
+			expectedError: "the output format can only be set when executing a command or a file",
+		},
+		"unsupported format": {
+			query:         ConsoleQueryOpts{Command: "SELECT 1", Format: ConsoleFormatTSV},
+			expectedError: "invalid format 'tsv', must be one of: table, csv",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.query.validate(context.Background(), ConsoleFormatTable, ConsoleFormatCSV)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConsoleQueryOpts_fetchClient(t *testing.T) {
+	tests := map[string]struct {
+		query           ConsoleQueryOpts
+		expectedCommand []string
+	}{
+		"interactive console": {
+			expectedCommand: []string{"dbclient-fetcher", "pgsql", "&&"},
+		},
+		"command": {
+			query:           ConsoleQueryOpts{Command: "SELECT 1", Format: ConsoleFormatCSV},
+			expectedCommand: []string{"dbclient-fetcher", "pgsql", ">/dev/null", "&&"},
+		},
+		"file": {
+			query:           ConsoleQueryOpts{File: "script.sql"},
+			expectedCommand: []string{"dbclient-fetcher", "pgsql", ">/dev/null", "&&"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedCommand, test.query.fetchClient("pgsql"))
+		})
+	}
+}
+
+func TestQueryArgs(t *testing.T) {
+	tests := map[string]struct {
+		args         []string
+		expectedArgs string
+	}{
+		"PostgreSQL interactive": {
+			args: pgSQLQueryArgs(ConsoleQueryOpts{}),
+		},
+		"PostgreSQL CSV command": {
+			args:         pgSQLQueryArgs(ConsoleQueryOpts{Command: "SELECT 'a'", Format: ConsoleFormatCSV}),
+			expectedArgs: `--no-psqlrc --set ON_ERROR_STOP=1 --csv --command 'SELECT '\''a'\'''`,
+		},
+		"PostgreSQL JSON command": {
+			args:         pgSQLQueryArgs(ConsoleQueryOpts{Command: "SELECT id FROM users;", Format: ConsoleFormatJSON}),
+			expectedArgs: `--no-psqlrc --set ON_ERROR_STOP=1 --tuples-only --no-align --quiet --command 'SELECT coalesce(json_agg(query), '\''[]'\''::json) FROM (SELECT id FROM users) AS query'`,
+		},
+		"PostgreSQL file": {
+			args:         pgSQLQueryArgs(ConsoleQueryOpts{File: "scripts/migration.sql"}),
+			expectedArgs: `--no-psqlrc --set ON_ERROR_STOP=1 --file '/tmp/uploads/migration.sql'`,
+		},
+		"MySQL TSV command": {
+			args:         mySQLQueryArgs(ConsoleQueryOpts{Command: "SELECT 1", Format: ConsoleFormatTSV}),
+			expectedArgs: `--batch --execute 'SELECT 1'`,
+		},
+		"MySQL file": {
+			args:         mySQLQueryArgs(ConsoleQueryOpts{File: "migration.sql"}),
+			expectedArgs: `--table < '/tmp/uploads/migration.sql'`,
+		},
+		"InfluxDB file": {
+			args:         influxDBQueryArgs(ConsoleQueryOpts{File: "queries.txt", Format: ConsoleFormatJSON}),
+			expectedArgs: `-format json -execute "$(cat '/tmp/uploads/queries.txt')"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expectedArgs, strings.Join(test.args, " "))
+		})
+	}
+}
+
+func TestRedisQueryArgs(t *testing.T) {
+	tests := map[string]struct {
+		query         ConsoleQueryOpts
+		expectedArgs  string
+		expectedError string
+	}{
+		"interactive": {},
+		"CSV command": {
+			query:        ConsoleQueryOpts{Command: "LRANGE queue 0 -1", Format: ConsoleFormatCSV},
+			expectedArgs: `--csv 'LRANGE' 'queue' '0' '-1'`,
+		},
+		"command with a glob": {
+			query:        ConsoleQueryOpts{Command: "KEYS *"},
+			expectedArgs: `'KEYS' '*'`,
+		},
+		"value with shell metacharacters": {
+			query:        ConsoleQueryOpts{Command: `SET key "a; rm -rf / | $(id)"`},
+			expectedArgs: `'SET' 'key' 'a; rm -rf / | $(id)'`,
+		},
+		"file": {
+			query:        ConsoleQueryOpts{File: "commands.txt"},
+			expectedArgs: `< '/tmp/uploads/commands.txt'`,
+		},
+		"unterminated quote": {
+			query:         ConsoleQueryOpts{Command: `SET key "value`},
+			expectedError: "split the Redis command into words",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			args, err := redisQueryArgs(t.Context(), test.query)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedArgs, strings.Join(args, " "))
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Scalingo/cli/apps"
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+	"github.com/Scalingo/gopassword"
+)
+
+// verifyCredentialsCommands are the commands connecting to the target
+// database, executed in a one-off container to verify the new credentials
+var verifyCredentialsCommands = map[string]string{
+	postgreSQLProviderID: `psql "$TARGET_DB_URL" --command 'SELECT 1' > /dev/null`,
+	mySQLProviderID:      mySQLTargetCommand + ` --execute 'SELECT 1' > /dev/null`,
+	mongoDBProviderID:    `mongo "$TARGET_DB_URL" --quiet --eval 'db.runCommand({ ping: 1 })' > /dev/null`,
+}
+
+// restartWaitHealthy is the maximal duration for a restarted container to be
+// running when the apps are restarted with the new credentials
+const restartWaitHealthy = 30 * time.Second
+
+type RotateCredentialsOpts struct {
+	App      string
+	AddonID  string
+	Username string
+	// Restart restarts the apps whose variables are updated with a rolling
+	// restart, container by container
+	Restart bool
+	// Yes skips the confirmation before rotating the password
+	Yes bool
+}
+
+// credentialsVariable is an environment variable containing a connection
+// URL of the database with the credentials of the rotated user
+type credentialsVariable struct {
+	App      string
+	Variable *scalingo.Variable
+	URL      *url.URL
+}
+
+// withPassword returns the value of the variable with the given password
+func (v credentialsVariable) withPassword(password string) string {
+	u := *v.URL
+	u.User = url.UserPassword(v.URL.User.Username(), password)
+	return u.String()
+}
+
+// RotateCredentials resets the password of a database user, then updates
+// the environment variables of the apps containing the credentials of this
+// user. The apps are optionally restarted and the new credentials are
+// verified from a one-off container of the app of the database.
+func RotateCredentials(ctx context.Context, opts RotateCredentialsOpts) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	addon, err := c.AddonShow(ctx, opts.App, opts.AddonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get addon information")
+	}
+	providerID, engine, err := addonEngine(ctx, addon, postgreSQLProviderID, mySQLProviderID, mongoDBProviderID)
+	if err != nil {
+		return err
+	}
+
+	databaseUsers, err := c.DatabaseListUsers(ctx, opts.App, opts.AddonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list the database's users")
+	}
+	userIndex := slices.IndexFunc(databaseUsers, func(user scalingo.DatabaseUser) bool {
+		return user.Name == opts.Username
+	})
+	if userIndex == -1 {
+		return errors.Newf(ctx, "user %s does not exist", opts.Username)
+	}
+	if databaseUsers[userIndex].Protected {
+		return errors.Newf(ctx, "user %s is protected, its credentials are managed by the platform", opts.Username)
+	}
+
+	databaseURL, _, _, err := dbURL(ctx, opts.App, engine.VariableName, engine.URLSchemes)
+	if err != nil {
+		return err
+	}
+	variables, err := findCredentialsVariables(ctx, c, databaseURL, opts.Username)
+	if err != nil {
+		return err
+	}
+
+	if len(variables) == 0 {
+		io.Warningf("No environment variable contains the credentials of %s, only its password is rotated\n", opts.Username)
+	} else {
+		io.Statusf("Environment variables containing the credentials of %s:\n", opts.Username)
+		for _, variable := range variables {
+			io.Infof("%s: %s\n", variable.App, variable.Variable.Name)
+		}
+	}
+	if !opts.Yes && !utils.Confirm(fmt.Sprintf("Rotate the password of %s and update these variables?", opts.Username)) {
+		return errors.New(ctx, "rotation not confirmed, aborting")
+	}
+
+	password, err := resetUserPassword(ctx, c, opts.App, opts.AddonID, opts.Username)
+	if err != nil {
+		return err
+	}
+	io.Statusf("The password of %s has been rotated\n", opts.Username)
+
+	updatedApps, err := updateCredentialsVariables(ctx, c, variables, password)
+	if err != nil {
+		// Without the password, the variables could not be fixed manually
+		io.Warningf("The new password of %s is: %s\n", opts.Username, password)
+		return err
+	}
+
+	if opts.Restart {
+		for _, app := range updatedApps {
+			io.Statusf("Restarting %s container by container\n", app)
+			err := apps.RollingRestart(ctx, app, apps.RollingRestartOpts{
+				Batch:       1,
+				WaitHealthy: restartWaitHealthy,
+			})
+			if err != nil {
+				return errors.Wrapf(ctx, err, "restart %s", app)
+			}
+		}
+	} else if len(updatedApps) > 0 {
+		io.Infof("Restart %s to use the new credentials\n", strings.Join(updatedApps, ", "))
+	}
+
+	verifiedURL := *databaseURL
+	verifiedURL.User = url.UserPassword(opts.Username, password)
+	io.Statusf("Verifying the new credentials of %s from a one-off container of %s\n", opts.Username, opts.App)
+	err = apps.Run(ctx, apps.RunOpts{
+		DisplayCmd: "database-credentials-rotate " + opts.Username,
+		App:        opts.App,
+		Cmd:        []string{"dbclient-fetcher", engine.Fetcher, "&&", verifyCredentialsCommands[providerID], "&&", "echo 'The new credentials are valid'"},
+		CmdEnv:     connectionEnv(targetDBEnvPrefix, &verifiedURL),
+		Silent:     true,
+	})
+	if err != nil {
+		return errors.Wrap(ctx, err, "verify the new credentials in a one-off container")
+	}
+	return nil
+}
+
+// resetUserPassword resets the password of the user and returns the new
+// password. If the password generated by the database API is not returned,
+// a password is generated and set.
+func resetUserPassword(ctx context.Context, c *scalingo.Client, app, addonID, username string) (string, error) {
+	user, err := c.DatabaseUserResetPassword(ctx, app, addonID, username)
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "reset password of %s", username)
+	}
+	if user.Password != "" {
+		return user.Password, nil
+	}
+
+	password := gopassword.Generate()
+	_, err = c.DatabaseUpdateUser(ctx, app, addonID, username, scalingo.DatabaseUpdateUserParam{
+		DatabaseID:           addonID,
+		Password:             password,
+		PasswordConfirmation: password,
+	})
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "update password of %s", username)
+	}
+	return password, nil
+}
+
+// findCredentialsVariables looks for the variables containing the
+// credentials of the user in all the apps of the user
+func findCredentialsVariables(ctx context.Context, c *scalingo.Client, databaseURL *url.URL, username string) ([]credentialsVariable, error) {
+	appsList, err := c.AppsList(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "list apps")
+	}
+
+	variablesByApp := make([][]credentialsVariable, len(appsList))
+	errs := make([]error, len(appsList))
+	semaphore := make(chan struct{}, listAddonsConcurrency)
+	wg := &sync.WaitGroup{}
+	for i, app := range appsList {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			variables, err := c.VariablesListWithoutAlias(ctx, app.Name)
+			if err != nil {
+				errs[i] = errors.Wrapf(ctx, err, "list variables of %s", app.Name)
+				return
+			}
+			variablesByApp[i] = credentialsVariables(app.Name, variables, databaseURL, username)
+		}()
+	}
+	wg.Wait()
+
+	var variables []credentialsVariable
+	for i := range appsList {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		variables = append(variables, variablesByApp[i]...)
+	}
+	return variables, nil
+}
+
+// credentialsVariables returns the variables whose value is a connection URL
+// to the host of the database with the credentials of the user. The aliases
+// to other variables are not returned as they are updated with the variable
+// they reference.
+func credentialsVariables(app string, variables scalingo.Variables, databaseURL *url.URL, username string) []credentialsVariable {
+	var res []credentialsVariable
+	for _, variable := range variables {
+		if !strings.Contains(variable.Value, "://") {
+			continue
+		}
+		u, err := url.Parse(variable.Value)
+		if err != nil || u.User == nil {
+			continue
+		}
+		if u.Host == databaseURL.Host && u.User.Username() == username {
+			res = append(res, credentialsVariable{App: app, Variable: variable, URL: u})
+		}
+	}
+	return res
+}
+
+// updateCredentialsVariables sets the new password in the variables. It
+// returns the updated apps.
+func updateCredentialsVariables(ctx context.Context, c *scalingo.Client, variables []credentialsVariable, password string) ([]string, error) {
+	var updatedApps []string
+	var failedApps []string
+	for _, variable := range variables {
+		if slices.Contains(updatedApps, variable.App) || slices.Contains(failedApps, variable.App) {
+			continue
+		}
+		var appVariables scalingo.Variables
+		for _, v := range variables {
+			if v.App == variable.App {
+				appVariables = append(appVariables, &scalingo.Variable{Name: v.Variable.Name, Value: v.withPassword(password)})
+			}
+		}
+
+		_, err := c.VariableMultipleSet(ctx, variable.App, appVariables)
+		if err != nil {
+			io.Errorf("Fail to update the variables of %s: %v\n", variable.App, err)
+			failedApps = append(failedApps, variable.App)
+			continue
+		}
+		io.Statusf("The variables of %s have been updated\n", variable.App)
+		updatedApps = append(updatedApps, variable.App)
+	}
+
+	if len(failedApps) > 0 {
+		return updatedApps, errors.Newf(ctx, "fail to update the variables of %s", strings.Join(failedApps, ", "))
+	}
+	return updatedApps, nil
+}
@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// dbEngine gathers what the CLI needs to know about a database addon to run
+// its client tools in a one-off container.
+type dbEngine struct {
+	// Name is the human readable name of the engine
+	Name string
+	// VariableName is the prefix of the environment variable containing the
+	// connection URL of the database
+	VariableName string
+	// URLSchemes are the schemes accepted for the connection URL
+	URLSchemes []string
+	// Fetcher is the argument given to dbclient-fetcher to install the client
+	// tools in the one-off container
+	Fetcher string
+}
+
+const (
+	postgreSQLProviderID = "postgresql"
+	mySQLProviderID      = "mysql"
+	mongoDBProviderID    = "mongodb"
+	redisProviderID      = "redis"
+)
+
+var dbEngines = map[string]dbEngine{
+	postgreSQLProviderID: {
+		Name:         "PostgreSQL",
+		VariableName: "SCALINGO_POSTGRESQL",
+		URLSchemes:   []string{"postgres", "postgis", "postgresql"},
+		Fetcher:      "pgsql",
+	},
+	mySQLProviderID: {
+		Name:         "MySQL",
+		VariableName: "SCALINGO_MYSQL",
+		URLSchemes:   []string{"mysql", "mysql2"},
+		Fetcher:      "mysql",
+	},
+	mongoDBProviderID: {
+		Name:         "MongoDB",
+		VariableName: "SCALINGO_MONGO",
+		URLSchemes:   []string{"mongodb"},
+		Fetcher:      "mongo",
+	},
+	redisProviderID: {
+		Name:         "Redis",
+		VariableName: "SCALINGO_REDIS",
+		URLSchemes:   []string{"redis", "rediss"},
+		Fetcher:      "redis",
+	},
+}
+
+const (
+	// sourceDBEnvPrefix and targetDBEnvPrefix prefix the environment variables
+	// describing the databases in the one-off containers, see connectionEnv
+	sourceDBEnvPrefix = "SOURCE_DB"
+	targetDBEnvPrefix = "TARGET_DB"
+
+	oneOffUploadsDir = "/tmp/uploads"
+
+	// Commands restoring data into the target database, reading the data on
+	// stdin (except for mongorestore)
+	pgRestoreCommand    = `pg_restore --clean --if-exists --no-owner --no-acl --dbname "$TARGET_DB_URL"`
+	mySQLTargetCommand  = `mysql -h "$TARGET_DB_HOST" -P "$TARGET_DB_PORT" -u "$TARGET_DB_USER" --password="$TARGET_DB_PASSWORD" "$TARGET_DB_NAME"`
+	mongoRestoreCommand = `mongorestore --drop --uri="$TARGET_DB_URL" --nsFrom="$SOURCE_DB_NAME.*" --nsTo="$TARGET_DB_NAME.*"`
+)
+
+// mongoDumpDirCommands returns the commands looking for the dump of a MongoDB
+// database extracted in dir. They export SOURCE_DUMP_DIR, the root directory
+// of the dump, and SOURCE_DB_NAME, the name of the dumped database which is
+// the name of its directory.
+func mongoDumpDirCommands(dir string) []string {
+	return []string{
+		`export SOURCE_DB_DIR=$(dirname "$(find ` + shellQuote(dir) + ` -name '*.bson' | head -n 1)")`,
+		`export SOURCE_DB_NAME=$(basename "$SOURCE_DB_DIR")`,
+		`export SOURCE_DUMP_DIR=$(dirname "$SOURCE_DB_DIR")`,
+	}
+}
+
+// addonEngine returns the identifier and the engine of the given addon.
+func addonEngine(ctx context.Context, addon scalingo.Addon, supported ...string) (string, dbEngine, error) {
+	if addon.AddonProvider == nil {
+		return "", dbEngine{}, errors.Newf(ctx, "unknown provider for addon %s", addon.ID)
+	}
+	for _, providerID := range supported {
+		if addon.AddonProvider.ID == providerID {
+			return providerID, dbEngines[providerID], nil
+		}
+	}
+	return "", dbEngine{}, errors.Newf(ctx, "%s addons are not supported by this command", addon.AddonProvider.Name)
+}
+
+// connectionEnv returns the environment variables describing a connection URL
+// to give to a one-off container, each one prefixed by prefix: _URL, _HOST,
+// _PORT, _USER, _PASSWORD and _NAME (name of the database). Empty values are
+// omitted.
+func connectionEnv(prefix string, u *url.URL) []string {
+	password, _ := u.User.Password()
+	values := [][2]string{
+		{"URL", u.String()},
+		{"HOST", u.Hostname()},
+		{"PORT", u.Port()},
+		{"USER", u.User.Username()},
+		{"PASSWORD", password},
+		{"NAME", strings.TrimPrefix(u.Path, "/")},
+	}
+
+	env := make([]string, 0, len(values))
+	for _, v := range values {
+		if v[1] != "" {
+			env = append(env, prefix+"_"+v[0]+"="+v[1])
+		}
+	}
+	return env
+}
+
+// shellQuote quotes s so that it is interpreted literally by a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
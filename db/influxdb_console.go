@@ -13,12 +13,17 @@ type InfluxDBConsoleOpts struct {
 	App          string
 	Size         string
 	VariableName string
+	Query        ConsoleQueryOpts
 }
 
 func InfluxDBConsole(ctx context.Context, opts InfluxDBConsoleOpts) error {
 	if opts.VariableName == "" {
 		opts.VariableName = "SCALINGO_INFLUX"
 	}
+	err := opts.Query.validate(ctx, ConsoleFormatTable, ConsoleFormatCSV, ConsoleFormatJSON)
+	if err != nil {
+		return err
+	}
 	influxdbURL, username, password, err := dbURL(ctx, opts.App, opts.VariableName, []string{"http", "https"})
 	if err != nil {
 		return errors.Wrapf(ctx, err, "resolve InfluxDB URL from %s", opts.VariableName)
@@ -29,13 +34,14 @@ func InfluxDBConsole(ctx context.Context, opts InfluxDBConsoleOpts) error {
 		return errors.Newf(ctx, "%v has an invalid host", influxdbURL)
 	}
 
-	cmd := []string{"dbclient-fetcher", "influxdb", "&&", "influx"}
+	cmd := append(opts.Query.fetchClient("influxdb"), "influx")
 
 	if influxdbURL.Scheme == "https" {
 		cmd = append(cmd, "-ssl", "-unsafeSsl")
 	}
 
 	cmd = append(cmd, "-host", host, "-port", port, "-username", username, "-password", password, "-database", influxdbURL.Path[1:])
+	cmd = append(cmd, influxDBQueryArgs(opts.Query)...)
 
 	runOpts := apps.RunOpts{
 		DisplayCmd: "influxdb-console " + strings.Split(host, ".")[0],
@@ -44,10 +50,29 @@ func InfluxDBConsole(ctx context.Context, opts InfluxDBConsoleOpts) error {
 		Size:       opts.Size,
 	}
 
-	err = apps.Run(ctx, runOpts)
+	err = runConsole(ctx, runOpts, opts.Query)
 	if err != nil {
 		return errors.Newf(ctx, "fail to run InfluxDB console: %v", err)
 	}
 
 	return nil
 }
+
+// influxDBQueryArgs returns the influx arguments to execute the query
+// non-interactively. The queries of a file are given to -execute as influx
+// can only import data from files.
+func influxDBQueryArgs(q ConsoleQueryOpts) []string {
+	if q.interactive() {
+		return nil
+	}
+
+	format := "column"
+	if q.Format == ConsoleFormatCSV || q.Format == ConsoleFormatJSON {
+		format = q.Format
+	}
+	args := []string{"-format", format}
+	if q.File != "" {
+		return append(args, "-execute", `"$(cat `+q.uploadedFile()+`)"`)
+	}
+	return append(args, "-execute", shellQuote(q.Command))
+}
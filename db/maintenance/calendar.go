@@ -0,0 +1,345 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/db"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+	"github.com/Scalingo/go-utils/pagination"
+)
+
+const calendarTimeFormat = "2006/01/02 15:04 MST"
+
+// pendingMaintenanceStatuses are the statuses of the maintenances which are
+// not executed yet
+var pendingMaintenanceStatuses = []scalingo.MaintenanceStatus{
+	scalingo.MaintenanceStatusScheduled,
+	scalingo.MaintenanceStatusNotified,
+	scalingo.MaintenanceStatusQueued,
+	scalingo.MaintenanceStatusRunning,
+}
+
+type CalendarOpts struct {
+	ProjectSlug string
+	// Location is the timezone used to display the dates and to read the
+	// freeze periods and the new maintenance window
+	Location *time.Location
+	// Weeks is the number of weeks of maintenance windows displayed
+	Weeks int
+	// ICalendarFile is the file where the calendar is exported, - for stdout
+	ICalendarFile string
+	// FreezePeriodsFile is a YAML file listing the periods without maintenance
+	FreezePeriodsFile string
+	// MoveConflictsTo is the new maintenance window of the databases whose
+	// window overlaps a freeze period, with the format <weekday>:<hour>
+	MoveConflictsTo string
+	// Yes skips the confirmation before moving the maintenance windows
+	Yes bool
+}
+
+// calendarDatabase gathers the maintenance information of a database addon
+type calendarDatabase struct {
+	App          string
+	AddonID      string
+	DatabaseType string
+	Window       scalingo.MaintenanceWindow
+	Pending      []*scalingo.Maintenance
+	// NextWindow is the next maintenance window, when the pending maintenances
+	// are executed
+	NextWindow timeRange
+	// Conflicts are the freeze periods overlapped by the maintenance window
+	Conflicts []FreezePeriod
+}
+
+type timeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Calendar displays the maintenance windows and the pending maintenances of
+// all the database addons of the user.
+func Calendar(ctx context.Context, opts CalendarOpts) error {
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+	if opts.Weeks <= 0 {
+		opts.Weeks = 4
+	}
+
+	var freezePeriods []FreezePeriod
+	if opts.FreezePeriodsFile != "" {
+		var err error
+		freezePeriods, err = ReadFreezePeriods(ctx, opts.FreezePeriodsFile, opts.Location)
+		if err != nil {
+			return errors.Wrap(ctx, err, "read freeze periods")
+		}
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	databases, err := listCalendarDatabases(ctx, c, opts.ProjectSlug)
+	if err != nil {
+		return err
+	}
+	if len(databases) == 0 {
+		io.Status("No database addon found")
+		return nil
+	}
+
+	var conflicting []*calendarDatabase
+	for _, database := range databases {
+		database.Conflicts = windowConflicts(database.Window, freezePeriods)
+		if len(database.Conflicts) > 0 {
+			conflicting = append(conflicting, database)
+		}
+	}
+
+	if opts.ICalendarFile != "" {
+		err := exportICalendar(ctx, opts.ICalendarFile, databases, time.Now())
+		if err != nil {
+			return errors.Wrap(ctx, err, "export iCalendar file")
+		}
+		if opts.ICalendarFile == "-" {
+			return nil
+		}
+		io.Statusf("Calendar exported to %s\n", opts.ICalendarFile)
+	}
+
+	renderCalendar(databases, opts.Location)
+	renderUpcomingWindows(databases, opts.Location, opts.Weeks)
+
+	if len(freezePeriods) == 0 {
+		return nil
+	}
+	if len(conflicting) == 0 {
+		io.Statusf("No maintenance window overlaps the %d freeze periods\n", len(freezePeriods))
+		return nil
+	}
+	io.Warningf("%d maintenance windows overlap a freeze period\n", len(conflicting))
+	if opts.MoveConflictsTo == "" {
+		io.Info("Use --move-conflicts-to <weekday>:<hour> to move them")
+		return nil
+	}
+
+	return moveWindows(ctx, c, conflicting, freezePeriods, opts)
+}
+
+func listCalendarDatabases(ctx context.Context, c *scalingo.Client, projectSlug string) ([]*calendarDatabase, error) {
+	databaseAddons, err := db.ListDatabaseAddons(ctx, c, projectSlug)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "list database addons")
+	}
+
+	now := time.Now()
+	databases := make([]*calendarDatabase, len(databaseAddons))
+	errs := make([]error, len(databaseAddons))
+	db.ForEachDatabaseAddon(databaseAddons, func(i int, databaseAddon db.DatabaseAddon) {
+		app, addonID := databaseAddon.App.Name, databaseAddon.Addon.ID
+		database, err := c.DatabaseShow(ctx, app, addonID)
+		if err != nil {
+			errs[i] = errors.Wrapf(ctx, err, "get database %s of %s", addonID, app)
+			return
+		}
+		maintenances, _, err := c.DatabaseListMaintenance(ctx, app, addonID, pagination.NewRequest(1, 50))
+		if err != nil {
+			errs[i] = errors.Wrapf(ctx, err, "list maintenances of %s of %s", addonID, app)
+			return
+		}
+
+		calendarDB := &calendarDatabase{
+			App:          app,
+			AddonID:      addonID,
+			DatabaseType: databaseAddon.Addon.AddonProvider.Name,
+			Window:       database.MaintenanceWindow,
+		}
+		status := scalingo.MaintenanceStatusNotified
+		for _, maintenance := range maintenances {
+			if slices.Contains(pendingMaintenanceStatuses, maintenance.Status) {
+				calendarDB.Pending = append(calendarDB.Pending, maintenance)
+				status = maintenance.Status
+			}
+		}
+		start, end := getNextLocalMaintenanceWindow(now, database.MaintenanceWindow, status)
+		calendarDB.NextWindow = timeRange{Start: start, End: end}
+		databases[i] = calendarDB
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	slices.SortStableFunc(databases, func(a, b *calendarDatabase) int {
+		return a.NextWindow.Start.Compare(b.NextWindow.Start)
+	})
+	return databases, nil
+}
+
+func renderCalendar(databases []*calendarDatabase, location *time.Location) {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"App", "Addon", "Maintenance Window", "Next Window", "Pending Maintenances", "Freeze Conflicts"})
+	for _, database := range databases {
+		pending := make([]string, 0, len(database.Pending))
+		for _, maintenance := range database.Pending {
+			pending = append(pending, fmt.Sprintf("%s (%s)", maintenance.Type, maintenance.Status))
+		}
+		conflicts := make([]string, 0, len(database.Conflicts))
+		for _, period := range database.Conflicts {
+			conflicts = append(conflicts, period.Name)
+		}
+
+		_ = t.Append([]string{
+			database.App,
+			fmt.Sprintf("%s (%s)", database.DatabaseType, database.AddonID),
+			utils.FormatMaintenanceWindowWithTimezone(database.Window, location),
+			database.NextWindow.Start.In(location).Format(calendarTimeFormat),
+			strings.Join(pending, "\n"),
+			strings.Join(conflicts, "\n"),
+		})
+	}
+	_ = t.Render()
+}
+
+// renderUpcomingWindows displays the maintenance windows of the next weeks
+// sorted by date, to spot the days when many databases are impacted.
+func renderUpcomingWindows(databases []*calendarDatabase, location *time.Location, weeks int) {
+	type occurrence struct {
+		timeRange
+		database *calendarDatabase
+	}
+
+	now := time.Now()
+	var occurrences []occurrence
+	for _, database := range databases {
+		for _, r := range windowOccurrences(database.Window, now, now.AddDate(0, 0, 7*weeks)) {
+			occurrences = append(occurrences, occurrence{timeRange: r, database: database})
+		}
+	}
+	slices.SortStableFunc(occurrences, func(a, b occurrence) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	fmt.Println()
+	io.Statusf("Maintenance windows of the next %d weeks (%s)\n", weeks, location)
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Start", "End", "App", "Addon"})
+	for _, o := range occurrences {
+		_ = t.Append([]string{
+			o.Start.In(location).Format(calendarTimeFormat),
+			o.End.In(location).Format(calendarTimeFormat),
+			o.database.App,
+			o.database.DatabaseType,
+		})
+	}
+	_ = t.Render()
+}
+
+// windowOccurrences returns the occurrences of the weekly maintenance window
+// overlapping the given period.
+func windowOccurrences(window scalingo.MaintenanceWindow, from, to time.Time) []timeRange {
+	from = from.UTC()
+	duration := time.Duration(window.DurationInHour) * time.Hour
+	dayDiff := (window.WeekdayUTC - int(from.Weekday()) + 7) % 7
+	// Start one week before to include an occurrence in progress at from
+	start := time.Date(from.Year(), from.Month(), from.Day()+dayDiff-7, window.StartingHourUTC, 0, 0, 0, time.UTC)
+
+	var occurrences []timeRange
+	for ; start.Before(to); start = start.AddDate(0, 0, 7) {
+		end := start.Add(duration)
+		if end.After(from) {
+			occurrences = append(occurrences, timeRange{Start: start, End: end})
+		}
+	}
+	return occurrences
+}
+
+// windowConflicts returns the freeze periods overlapped by an occurrence of
+// the maintenance window.
+func windowConflicts(window scalingo.MaintenanceWindow, freezePeriods []FreezePeriod) []FreezePeriod {
+	var conflicts []FreezePeriod
+	for _, period := range freezePeriods {
+		if len(windowOccurrences(window, period.Start, period.End)) > 0 {
+			conflicts = append(conflicts, period)
+		}
+	}
+	return conflicts
+}
+
+// moveWindows updates the maintenance window of the conflicting databases,
+// after a confirmation of the user.
+func moveWindows(ctx context.Context, c *scalingo.Client, databases []*calendarDatabase, freezePeriods []FreezePeriod, opts CalendarOpts) error {
+	weekday, hour, err := parseWindowStart(ctx, opts.MoveConflictsTo)
+	if err != nil {
+		return err
+	}
+	weekdayUTC, hourUTC := utils.ConvertDayAndHourToTimezone(weekday, hour, opts.Location, time.UTC)
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"App", "Addon", "Current Window", "New Window", "Remaining Conflicts"})
+	for _, database := range databases {
+		newWindow := database.Window
+		newWindow.WeekdayUTC = int(weekdayUTC)
+		newWindow.StartingHourUTC = hourUTC
+		remaining := make([]string, 0)
+		for _, period := range windowConflicts(newWindow, freezePeriods) {
+			remaining = append(remaining, period.Name)
+		}
+		_ = t.Append([]string{
+			database.App,
+			database.AddonID,
+			utils.FormatMaintenanceWindowWithTimezone(database.Window, opts.Location),
+			utils.FormatMaintenanceWindowWithTimezone(newWindow, opts.Location),
+			strings.Join(remaining, "\n"),
+		})
+	}
+	_ = t.Render()
+
+	if !opts.Yes && !utils.Confirm(fmt.Sprintf("Move the maintenance window of these %d databases?", len(databases))) {
+		return errors.New(ctx, "maintenance windows not moved")
+	}
+
+	for _, database := range databases {
+		_, err := c.DatabaseUpdateMaintenanceWindow(ctx, database.App, database.AddonID, scalingo.MaintenanceWindowParams{
+			WeekdayUTC:      utils.IntPtr(int(weekdayUTC)),
+			StartingHourUTC: utils.IntPtr(hourUTC),
+		})
+		if err != nil {
+			return errors.Wrapf(ctx, err, "update the maintenance window of %s of %s", database.AddonID, database.App)
+		}
+		io.Statusf("Maintenance window of %s (%s) moved\n", database.App, database.DatabaseType)
+	}
+	return nil
+}
+
+// parseWindowStart parses the start of a maintenance window with the format
+// <weekday>:<hour>, e.g. sunday:3.
+func parseWindowStart(ctx context.Context, value string) (time.Weekday, int, error) {
+	day, hourStr, ok := strings.Cut(value, ":")
+	if !ok {
+		return time.Sunday, 0, errors.Newf(ctx, "invalid maintenance window '%s', the format is <weekday>:<hour>", value)
+	}
+	weekday, err := utils.ParseWeekday(ctx, day)
+	if err != nil {
+		return time.Sunday, 0, err
+	}
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil || hour < 0 || hour > 23 {
+		return time.Sunday, 0, errors.Newf(ctx, "invalid starting hour '%s': it must be between 0 and 23", hourStr)
+	}
+	return weekday, hour, nil
+}
@@ -0,0 +1,102 @@
+package maintenance
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestWindowOccurrences(t *testing.T) {
+	// Sundays from 23:00 to 03:00 UTC
+	window := scalingo.MaintenanceWindow{WeekdayUTC: int(time.Sunday), StartingHourUTC: 23, DurationInHour: 4}
+
+	tests := map[string]struct {
+		from, to       time.Time
+		expectedStarts []time.Time
+	}{
+		"occurrences of the next two weeks": {
+			from:           dateAt(t, "Wednesday, 19-Jul-23 08:00:00 UTC"),
+			to:             dateAt(t, "Wednesday, 02-Aug-23 08:00:00 UTC"),
+			expectedStarts: []time.Time{dateAt(t, "Sunday, 23-Jul-23 23:00:00 UTC"), dateAt(t, "Sunday, 30-Jul-23 23:00:00 UTC")},
+		},
+		"occurrence in progress": {
+			from:           dateAt(t, "Monday, 24-Jul-23 01:00:00 UTC"),
+			to:             dateAt(t, "Monday, 24-Jul-23 02:00:00 UTC"),
+			expectedStarts: []time.Time{dateAt(t, "Sunday, 23-Jul-23 23:00:00 UTC")},
+		},
+		"no occurrence": {
+			from: dateAt(t, "Monday, 24-Jul-23 03:00:00 UTC"),
+			to:   dateAt(t, "Sunday, 30-Jul-23 23:00:00 UTC"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			occurrences := windowOccurrences(window, test.from, test.to)
+			require.Len(t, occurrences, len(test.expectedStarts))
+			for i, occurrence := range occurrences {
+				assert.Equal(t, test.expectedStarts[i], occurrence.Start)
+				assert.Equal(t, 4*time.Hour, occurrence.End.Sub(occurrence.Start))
+			}
+		})
+	}
+}
+
+func TestReadFreezePeriods(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "freeze.yml")
+	err = os.WriteFile(path, []byte(`freeze_periods:
+  - name: Black Friday
+    start: 2023-07-24
+    end: 2023-07-24
+  - start: 2023-08-01 08:00
+    end: 2023-08-01 20:00
+`), 0600)
+	require.NoError(t, err)
+
+	periods, err := ReadFreezePeriods(t.Context(), path, location)
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.Equal(t, "Black Friday", periods[0].Name)
+	assert.Equal(t, time.Date(2023, 7, 24, 0, 0, 0, 0, location), periods[0].Start)
+	assert.Equal(t, time.Date(2023, 7, 25, 0, 0, 0, 0, location), periods[0].End)
+	assert.Equal(t, "Freeze period 2023-08-01 08:00", periods[1].Name)
+
+	// The window starting on Sunday 23:00 UTC ends on Monday 03:00 UTC, during
+	// the first freeze period
+	window := scalingo.MaintenanceWindow{WeekdayUTC: int(time.Sunday), StartingHourUTC: 23, DurationInHour: 4}
+	conflicts := windowConflicts(window, periods)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "Black Friday", conflicts[0].Name)
+}
+
+func TestRenderICalendar(t *testing.T) {
+	now := dateAt(t, "Wednesday, 19-Jul-23 08:00:00 UTC")
+	databases := []*calendarDatabase{{
+		App:          "my-app",
+		AddonID:      "ad-1",
+		DatabaseType: "PostgreSQL",
+		Window:       scalingo.MaintenanceWindow{WeekdayUTC: int(time.Sunday), StartingHourUTC: 23, DurationInHour: 4},
+		Pending:      []*scalingo.Maintenance{{ID: "m-1", Type: "upgrade", Status: scalingo.MaintenanceStatusScheduled}},
+		NextWindow:   timeRange{Start: dateAt(t, "Sunday, 23-Jul-23 23:00:00 UTC"), End: dateAt(t, "Monday, 24-Jul-23 03:00:00 UTC")},
+	}}
+
+	calendar := string(renderICalendar(databases, now))
+
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.Contains(t, calendar, "DTSTART:20230723T230000Z\r\nDURATION:PT4H\r\nRRULE:FREQ=WEEKLY\r\n")
+	assert.Contains(t, calendar, "UID:m-1@scalingo.com\r\n")
+	assert.Contains(t, calendar, "DTEND:20230724T030000Z\r\n")
+	assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	for _, line := range strings.Split(calendar, "\r\n") {
+		assert.LessOrEqual(t, len(line), 75)
+	}
+}
@@ -0,0 +1,87 @@
+package maintenance
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// freezePeriodLayouts are the accepted formats of the boundaries of a freeze
+// period. A date without time means the whole day.
+var freezePeriodLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// FreezePeriod is a period during which no maintenance should happen, e.g.
+// the sales season
+type FreezePeriod struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+}
+
+type freezePeriodsFile struct {
+	FreezePeriods []struct {
+		Name  string `yaml:"name"`
+		Start string `yaml:"start"`
+		End   string `yaml:"end"`
+	} `yaml:"freeze_periods"`
+}
+
+// ReadFreezePeriods reads the freeze periods from a YAML file:
+//
+//	freeze_periods:
+//	  - name: Black Friday
+//	    start: 2026-11-26
+//	    end: 2026-11-30
+//
+// The dates are read in the given location.
+func ReadFreezePeriods(ctx context.Context, path string, location *time.Location) ([]FreezePeriod, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read %s", path)
+	}
+
+	var file freezePeriodsFile
+	err = yaml.Unmarshal(content, &file)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse %s", path)
+	}
+
+	periods := make([]FreezePeriod, 0, len(file.FreezePeriods))
+	for i, p := range file.FreezePeriods {
+		name := p.Name
+		if name == "" {
+			name = "Freeze period " + p.Start
+		}
+		start, _, err := parseFreezePeriodTime(ctx, p.Start, location)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid start of freeze period %d", i+1)
+		}
+		end, dateOnly, err := parseFreezePeriodTime(ctx, p.End, location)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "invalid end of freeze period %d", i+1)
+		}
+		if dateOnly {
+			// The end date is included
+			end = end.AddDate(0, 0, 1)
+		}
+		if !end.After(start) {
+			return nil, errors.Newf(ctx, "freeze period '%s' ends before it starts", name)
+		}
+		periods = append(periods, FreezePeriod{Name: name, Start: start, End: end})
+	}
+	return periods, nil
+}
+
+func parseFreezePeriodTime(ctx context.Context, value string, location *time.Location) (time.Time, bool, error) {
+	for _, layout := range freezePeriodLayouts {
+		t, err := time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return t, layout == "2006-01-02", nil
+		}
+	}
+	return time.Time{}, false, errors.Newf(ctx, "'%s' is not a date (YYYY-MM-DD) or a date and time (YYYY-MM-DD HH:MM)", value)
+}
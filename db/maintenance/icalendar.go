@@ -0,0 +1,101 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const iCalendarTimeFormat = "20060102T150405Z"
+
+var iCalendarTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func exportICalendar(ctx context.Context, path string, databases []*calendarDatabase, now time.Time) error {
+	content := renderICalendar(databases, now)
+	if path == "-" {
+		_, err := os.Stdout.Write(content)
+		if err != nil {
+			return errors.Wrap(ctx, err, "write calendar")
+		}
+		return nil
+	}
+
+	err := os.WriteFile(path, content, 0644)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "write %s", path)
+	}
+	return nil
+}
+
+// renderICalendar returns an iCalendar (RFC 5545) document with a weekly
+// recurring event per maintenance window and an event per pending
+// maintenance, planned during the next maintenance window.
+func renderICalendar(databases []*calendarDatabase, now time.Time) []byte {
+	buffer := new(bytes.Buffer)
+	writeLine := func(line string) {
+		buffer.WriteString(foldICalendarLine(line))
+		buffer.WriteString("\r\n")
+	}
+	stamp := now.UTC().Format(iCalendarTimeFormat)
+
+	writeLine("BEGIN:VCALENDAR")
+	writeLine("VERSION:2.0")
+	writeLine("PRODID:-//Scalingo//Scalingo CLI//EN")
+	writeLine("CALSCALE:GREGORIAN")
+	for _, database := range databases {
+		occurrences := windowOccurrences(database.Window, now, now.AddDate(0, 0, 7))
+		if len(occurrences) > 0 {
+			writeLine("BEGIN:VEVENT")
+			writeLine("UID:" + database.AddonID + "-maintenance-window@scalingo.com")
+			writeLine("DTSTAMP:" + stamp)
+			writeLine("DTSTART:" + occurrences[0].Start.Format(iCalendarTimeFormat))
+			writeLine(fmt.Sprintf("DURATION:PT%dH", database.Window.DurationInHour))
+			writeLine("RRULE:FREQ=WEEKLY")
+			writeLine("SUMMARY:" + iCalendarTextEscaper.Replace(fmt.Sprintf("Maintenance window of %s (%s)", database.App, database.DatabaseType)))
+			writeLine("DESCRIPTION:" + iCalendarTextEscaper.Replace("Addon "+database.AddonID))
+			writeLine("TRANSP:TRANSPARENT")
+			writeLine("END:VEVENT")
+		}
+
+		for _, maintenance := range database.Pending {
+			writeLine("BEGIN:VEVENT")
+			writeLine("UID:" + maintenance.ID + "@scalingo.com")
+			writeLine("DTSTAMP:" + stamp)
+			writeLine("DTSTART:" + database.NextWindow.Start.UTC().Format(iCalendarTimeFormat))
+			writeLine("DTEND:" + database.NextWindow.End.UTC().Format(iCalendarTimeFormat))
+			writeLine("SUMMARY:" + iCalendarTextEscaper.Replace(fmt.Sprintf("Maintenance %s of %s (%s)", maintenance.Type, database.App, database.DatabaseType)))
+			writeLine("DESCRIPTION:" + iCalendarTextEscaper.Replace(fmt.Sprintf("Maintenance %s of the addon %s, status: %s", maintenance.ID, database.AddonID, maintenance.Status)))
+			writeLine("END:VEVENT")
+		}
+	}
+	writeLine("END:VCALENDAR")
+
+	return buffer.Bytes()
+}
+
+// foldICalendarLine splits the lines longer than 75 octets, the continuation
+// lines start with a space.
+func foldICalendarLine(line string) string {
+	const maxLength = 75
+	if len(line) <= maxLength {
+		return line
+	}
+
+	var folded strings.Builder
+	length := 0
+	for _, r := range line {
+		runeLength := len(string(r))
+		if length+runeLength > maxLength {
+			folded.WriteString("\r\n ")
+			length = 1
+		}
+		folded.WriteRune(r)
+		length += runeLength
+	}
+	return folded.String()
+}
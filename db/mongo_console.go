@@ -11,31 +11,59 @@ type MongoConsoleOpts struct {
 	App          string
 	Size         string
 	VariableName string
+	Query        ConsoleQueryOpts
 }
 
 func MongoConsole(ctx context.Context, opts MongoConsoleOpts) error {
 	if opts.VariableName == "" {
 		opts.VariableName = "SCALINGO_MONGO"
 	}
+	err := opts.Query.validate(ctx, ConsoleFormatTable, ConsoleFormatJSON)
+	if err != nil {
+		return err
+	}
+	if opts.Query.Format == ConsoleFormatJSON && opts.Query.File != "" {
+		return errors.New(ctx, "the JSON format is only available when executing a command")
+	}
 	mongoURL, _, _, err := dbURL(ctx, opts.App, opts.VariableName, []string{"mongodb"})
 	if err != nil {
 		return errors.Wrapf(ctx, err, "resolve MongoDB URL from %s", opts.VariableName)
 	}
 
-	command := []string{"dbclient-fetcher", "mongo", "&&", "mongo"}
+	command := append(opts.Query.fetchClient("mongo"), "mongo")
 	if mongoURL.Query().Get("ssl") == "true" {
 		command = append(command, "--ssl", "--sslAllowInvalidCertificates")
 	}
 
-	err = apps.Run(ctx, apps.RunOpts{
+	command = append(command, "'"+mongoURL.String()+"'")
+	err = runConsole(ctx, apps.RunOpts{
 		DisplayCmd: "mongo-console",
 		App:        opts.App,
-		Cmd:        append(command, "'"+mongoURL.String()+"'"),
+		Cmd:        append(command, mongoQueryArgs(opts.Query)...),
 		Size:       opts.Size,
-	})
+	}, opts.Query)
 	if err != nil {
 		return errors.Newf(ctx, "fail to run MongoDB console: %v", err)
 	}
 
 	return nil
 }
+
+// mongoQueryArgs returns the mongo shell arguments to execute the query
+// non-interactively. A JSON output is built by serializing the result of the
+// query, cursors are converted to arrays.
+func mongoQueryArgs(q ConsoleQueryOpts) []string {
+	if q.interactive() {
+		return nil
+	}
+
+	args := []string{"--quiet"}
+	if q.File != "" {
+		return append(args, q.uploadedFile())
+	}
+	command := q.Command
+	if q.Format == ConsoleFormatJSON {
+		command = "var result = (" + command + "); if (result && typeof result.toArray === 'function') { result = result.toArray(); } print(JSON.stringify(result));"
+	}
+	return append(args, "--eval", shellQuote(command))
+}
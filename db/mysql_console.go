@@ -13,12 +13,17 @@ type MySQLConsoleOpts struct {
 	App          string
 	Size         string
 	VariableName string
+	Query        ConsoleQueryOpts
 }
 
 func MySQLConsole(ctx context.Context, opts MySQLConsoleOpts) error {
 	if opts.VariableName == "" {
 		opts.VariableName = "SCALINGO_MYSQL"
 	}
+	err := opts.Query.validate(ctx, ConsoleFormatTable, ConsoleFormatTSV)
+	if err != nil {
+		return err
+	}
 	mySQLURL, user, password, err := dbURL(ctx, opts.App, opts.VariableName, []string{"mysql", "mysql2"})
 	if err != nil {
 		return errors.Wrapf(ctx, err, "resolve MySQL URL from %s", opts.VariableName)
@@ -32,14 +37,31 @@ func MySQLConsole(ctx context.Context, opts MySQLConsoleOpts) error {
 	runOpts := apps.RunOpts{
 		DisplayCmd: "mysql-console " + user,
 		App:        opts.App,
-		Cmd:        []string{"dbclient-fetcher", "mysql", "&&", "mysql", "-h", host, "-P", port, fmt.Sprintf("--password=%v", password), "-u", user, user},
+		Cmd:        append(opts.Query.fetchClient("mysql"), append([]string{"mysql", "-h", host, "-P", port, fmt.Sprintf("--password=%v", password), "-u", user, user}, mySQLQueryArgs(opts.Query)...)...),
 		Size:       opts.Size,
 	}
 
-	err = apps.Run(ctx, runOpts)
+	err = runConsole(ctx, runOpts, opts.Query)
 	if err != nil {
 		return errors.Newf(ctx, "fail to run MySQL console: %v", err)
 	}
 
 	return nil
 }
+
+// mySQLQueryArgs returns the mysql arguments to execute the query
+// non-interactively. The TSV format is the batch output of mysql.
+func mySQLQueryArgs(q ConsoleQueryOpts) []string {
+	if q.interactive() {
+		return nil
+	}
+
+	args := []string{"--table"}
+	if q.Format == ConsoleFormatTSV {
+		args = []string{"--batch"}
+	}
+	if q.File != "" {
+		return append(args, "<", q.uploadedFile())
+	}
+	return append(args, "--execute", shellQuote(q.Command))
+}
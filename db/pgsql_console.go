@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"strings"
 
 	"github.com/Scalingo/cli/apps"
 	"github.com/Scalingo/go-utils/errors/v3"
@@ -11,12 +12,20 @@ type PgSQLConsoleOpts struct {
 	App          string
 	Size         string
 	VariableName string
+	Query        ConsoleQueryOpts
 }
 
 func PgSQLConsole(ctx context.Context, opts PgSQLConsoleOpts) error {
 	if opts.VariableName == "" {
 		opts.VariableName = "SCALINGO_POSTGRESQL"
 	}
+	err := opts.Query.validate(ctx, ConsoleFormatTable, ConsoleFormatCSV, ConsoleFormatJSON)
+	if err != nil {
+		return err
+	}
+	if opts.Query.Format == ConsoleFormatJSON && opts.Query.File != "" {
+		return errors.New(ctx, "the JSON format is only available when executing a command")
+	}
 	postgreSQLURL, user, _, err := dbURL(ctx, opts.App, opts.VariableName, []string{"postgres", "postgis", "postgresql"})
 	if err != nil {
 		return errors.Wrapf(ctx, err, "resolve PostgreSQL URL from %s", opts.VariableName)
@@ -25,14 +34,38 @@ func PgSQLConsole(ctx context.Context, opts PgSQLConsoleOpts) error {
 	runOpts := apps.RunOpts{
 		DisplayCmd: "pgsql-console " + user,
 		App:        opts.App,
-		Cmd:        []string{"dbclient-fetcher", "pgsql", "&&", "psql", "'" + postgreSQLURL.String() + "'"},
+		Cmd:        append(opts.Query.fetchClient("pgsql"), append([]string{"psql", "'" + postgreSQLURL.String() + "'"}, pgSQLQueryArgs(opts.Query)...)...),
 		Size:       opts.Size,
 	}
 
-	err = apps.Run(ctx, runOpts)
+	err = runConsole(ctx, runOpts, opts.Query)
 	if err != nil {
 		return errors.Newf(ctx, "fail to run PostgreSQL console: %v", err)
 	}
 
 	return nil
 }
+
+// pgSQLQueryArgs returns the psql arguments to execute the query
+// non-interactively. A JSON output is built by aggregating the rows of the
+// query with json_agg.
+func pgSQLQueryArgs(q ConsoleQueryOpts) []string {
+	if q.interactive() {
+		return nil
+	}
+
+	args := []string{"--no-psqlrc", "--set", "ON_ERROR_STOP=1"}
+	command := q.Command
+	switch q.Format {
+	case ConsoleFormatCSV:
+		args = append(args, "--csv")
+	case ConsoleFormatJSON:
+		args = append(args, "--tuples-only", "--no-align", "--quiet")
+		command = "SELECT coalesce(json_agg(query), '[]'::json) FROM (" + strings.TrimRight(command, "; \n") + ") AS query"
+	}
+
+	if q.File != "" {
+		return append(args, "--file", q.uploadedFile())
+	}
+	return append(args, "--command", shellQuote(command))
+}
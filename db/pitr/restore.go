@@ -3,34 +3,185 @@ package pitr
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
+	"github.com/briandowns/spinner"
+	"github.com/olekukonko/tablewriter"
+
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
 	"github.com/Scalingo/go-utils/errors/v3"
 )
 
-func Restore(ctx context.Context, currentResource, addonName string, restoreTime time.Time) error {
+const (
+	restorePollInterval = 5 * time.Second
+	// restoreStartTimeout is the time for the restore operation to become the
+	// current operation of the database
+	restoreStartTimeout = 2 * time.Minute
+	restoreTimeout      = 3 * time.Hour
+)
+
+type RestoreOpts struct {
+	RestoreTime time.Time
+	// Location is the timezone of the displayed dates
+	Location *time.Location
+	// Confirm skips the interactive confirmation if it is the name of the app
+	Confirm string
+}
+
+// Restore restores a database to a point in time of its recovery window. A
+// preview of the restore is displayed before asking for confirmation, then
+// the restore operation is followed until completion.
+func Restore(ctx context.Context, currentResource, addonName string, opts RestoreOpts) error {
 	c, err := config.ScalingoClient(ctx)
 	if err != nil {
 		return errors.Wrapf(ctx, err, "get Scalingo client")
 	}
+	if opts.Location == nil {
+		opts.Location = time.Local
+	}
+
+	recoveryWindow, err := c.DatabaseGetPITRRecoveryWindow(ctx, currentResource, addonName)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get PITR recovery window")
+	}
+	err = checkRecoveryWindow(ctx, recoveryWindow, opts.RestoreTime, opts.Location)
+	if err != nil {
+		return err
+	}
 
-	io.Warning("This operation is irreversible and previous data will be restore")
-	io.Info("Do you want to confirm? (y/n)")
+	backups, err := c.BackupList(ctx, currentResource, addonName)
+	if err != nil {
+		io.Warningf("Fail to list the backups of the database: %v\n", err)
+	}
+	renderRestorePreview(recoveryWindow, opts.RestoreTime, backups, opts.Location)
 
-	var confirm string
-	_, _ = fmt.Scanln(&confirm)
-	if confirm != "y" && confirm != "Y" {
-		return errors.New(ctx, "You didn't confirm, aborting…")
+	io.Warning("This operation is irreversible: the data written after the restore time will be lost")
+	if opts.Confirm != currentResource {
+		err := utils.ConfirmByTyping(ctx, fmt.Sprintf("The database of %s will be restored to %s.", currentResource, formatTime(opts.RestoreTime, opts.Location)), currentResource)
+		if err != nil {
+			return err
+		}
 	}
 
-	operationID, err := c.DatabaseRestorePITR(ctx, currentResource, addonName, restoreTime)
+	operationID, err := c.DatabaseRestorePITR(ctx, currentResource, addonName, opts.RestoreTime)
 	if err != nil {
 		return errors.Wrap(ctx, err, "restore database")
 	}
-
 	io.Statusf("Database restore operation %s has been created\n", operationID)
 
+	err = waitForRestore(ctx, c, currentResource, addonName, operationID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "wait for the end of the restore")
+	}
+	return nil
+}
+
+// checkRecoveryWindow returns an error if the restore time is outside of the
+// recovery window
+func checkRecoveryWindow(ctx context.Context, recoveryWindow scalingo.DatabasePITRRecoveryWindow, restoreTime time.Time, loc *time.Location) error {
+	if recoveryWindow.EarliestRecoverableAt == nil || recoveryWindow.LatestRecoverableAt == nil {
+		return errors.New(ctx, "the database has no recovery window, is point-in-time recovery enabled?")
+	}
+	earliest, latest := *recoveryWindow.EarliestRecoverableAt, *recoveryWindow.LatestRecoverableAt
+	if restoreTime.Before(earliest) {
+		return errors.Newf(ctx, "%s is before the recovery window, the earliest recoverable time is %s",
+			formatTime(restoreTime, loc), formatTime(earliest, loc))
+	}
+	if restoreTime.After(latest) {
+		return errors.Newf(ctx, "%s is after the recovery window, the latest recoverable time is %s",
+			formatTime(restoreTime, loc), formatTime(latest, loc))
+	}
 	return nil
 }
+
+// nearestBackups returns the last successful backup before the restore time
+// and the first one after it, nil if there is none.
+func nearestBackups(backups []scalingo.Backup, restoreTime time.Time) (*scalingo.Backup, *scalingo.Backup) {
+	var before, after *scalingo.Backup
+	for i, backup := range backups {
+		if backup.Status != scalingo.BackupStatusDone {
+			continue
+		}
+		if !backup.CreatedAt.After(restoreTime) {
+			if before == nil || backup.CreatedAt.After(before.CreatedAt) {
+				before = &backups[i]
+			}
+		} else if after == nil || backup.CreatedAt.Before(after.CreatedAt) {
+			after = &backups[i]
+		}
+	}
+	return before, after
+}
+
+func renderRestorePreview(recoveryWindow scalingo.DatabasePITRRecoveryWindow, restoreTime time.Time, backups []scalingo.Backup, loc *time.Location) {
+	formatBackup := func(backup *scalingo.Backup) string {
+		if backup == nil {
+			return "none"
+		}
+		delta := backup.CreatedAt.Sub(restoreTime).Round(time.Second)
+		if delta < 0 {
+			return fmt.Sprintf("%s (%s, %s before)", backup.Name, formatTime(backup.CreatedAt, loc), -delta)
+		}
+		return fmt.Sprintf("%s (%s, %s after)", backup.Name, formatTime(backup.CreatedAt, loc), delta)
+	}
+	before, after := nearestBackups(backups, restoreTime)
+
+	t := tablewriter.NewWriter(os.Stdout)
+	_ = t.Append([]string{"Restore time", io.Bold(formatTime(restoreTime, loc)) + " (" + restoreTime.UTC().Format(time.RFC3339) + ")"})
+	_ = t.Append([]string{"Recovery window", formatTime(*recoveryWindow.EarliestRecoverableAt, loc) + " → " + formatTime(*recoveryWindow.LatestRecoverableAt, loc)})
+	_ = t.Append([]string{"Data lost", "everything written after the restore time (" + time.Since(restoreTime).Round(time.Second).String() + ")"})
+	_ = t.Append([]string{"Nearest backup before", formatBackup(before)})
+	_ = t.Append([]string{"Nearest backup after", formatBackup(after)})
+	_ = t.Render()
+}
+
+// waitForRestore polls the database until the restore operation is not its
+// current operation anymore and the database is running.
+func waitForRestore(ctx context.Context, c *scalingo.Client, app, addonID, operationID string) error {
+	spin := spinner.New(spinner.CharSets[11], 100*time.Millisecond)
+	spin.Suffix = " Waiting for the restore to start"
+	spin.Start()
+	defer spin.Stop()
+
+	startedAt := time.Now()
+	started := false
+	ticker := time.NewTicker(restorePollInterval)
+	defer ticker.Stop()
+	for {
+		database, err := c.DatabaseShow(ctx, app, addonID)
+		if err != nil {
+			return errors.Wrap(ctx, err, "get database status")
+		}
+		elapsed := time.Since(startedAt).Round(time.Second)
+		switch {
+		case database.CurrentOperationID == operationID:
+			started = true
+			spin.Suffix = fmt.Sprintf(" Restoring the database (status: %s, %s elapsed)", database.Status, elapsed)
+		case started && database.Status == scalingo.DatabaseStatusRunning:
+			spin.Stop()
+			io.Statusf("The database has been restored in %s\n", elapsed)
+			return nil
+		case started:
+			spin.Suffix = fmt.Sprintf(" Waiting for the database to be running (status: %s, %s elapsed)", database.Status, elapsed)
+		case time.Since(startedAt) > restoreStartTimeout:
+			return errors.Newf(ctx, "restore operation %s did not start after %s, check the status of the database with 'scalingo addons'", operationID, restoreStartTimeout)
+		}
+		if time.Since(startedAt) > restoreTimeout {
+			return errors.Newf(ctx, "restore operation %s is not complete after %s, the database is %s", operationID, restoreTimeout, database.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func formatTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("2006-01-02 15:04:05 MST")
+}
@@ -0,0 +1,90 @@
+package pitr
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// localTimeFormats are the formats of the restore times without timezone,
+// interpreted in the timezone given to ParseRestoreTime
+var localTimeFormats = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// relativeTimeUnits are the units of the relative restore times, e.g.
+// "15 minutes ago"
+var relativeTimeUnits = map[string]time.Duration{
+	"s":       time.Second,
+	"sec":     time.Second,
+	"second":  time.Second,
+	"seconds": time.Second,
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+}
+
+// ParseRestoreTime parses a restore time. It can be:
+//   - an RFC 3339 time, e.g. 2026-07-18T23:00:00Z or 2026-07-18T23:00:00+02:00,
+//   - a time without timezone, interpreted in loc, e.g. "2026-07-18 23:00",
+//   - a time relative to now, e.g. "15 minutes ago", "2h ago" or "1h30m ago".
+func ParseRestoreTime(ctx context.Context, s string, now time.Time, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "now" {
+		return now, nil
+	}
+	if ago, ok := strings.CutSuffix(s, " ago"); ok {
+		d, err := parseRelativeDuration(ctx, strings.TrimSpace(ago))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t, nil
+	}
+	for _, format := range localTimeFormats {
+		t, err := time.ParseInLocation(format, s, loc)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.Newf(ctx, "invalid restore time '%s', expected e.g. 2026-07-18T23:00:00Z, '2026-07-18 23:00' or '15 minutes ago'", s)
+}
+
+func parseRelativeDuration(ctx context.Context, s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.ReplaceAll(s, " ", ""))
+	if err == nil {
+		return d, nil
+	}
+
+	amount, unit, ok := strings.Cut(s, " ")
+	if !ok {
+		return 0, errors.Newf(ctx, "invalid relative time '%s ago'", s)
+	}
+	n, err := strconv.Atoi(amount)
+	if err != nil || n < 0 {
+		return 0, errors.Newf(ctx, "invalid relative time '%s ago'", s)
+	}
+	unitDuration, ok := relativeTimeUnits[strings.ToLower(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, errors.Newf(ctx, "unknown time unit '%s'", unit)
+	}
+	return time.Duration(n) * unitDuration, nil
+}
@@ -0,0 +1,64 @@
+package pitr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRestoreTime(t *testing.T) {
+	now := time.Date(2026, 7, 18, 23, 0, 0, 0, time.UTC)
+	paris, err := time.LoadLocation("Europe/Paris")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		input         string
+		expectedTime  time.Time
+		expectedError string
+	}{
+		"RFC 3339": {
+			input:        "2026-07-18T21:30:00Z",
+			expectedTime: time.Date(2026, 7, 18, 21, 30, 0, 0, time.UTC),
+		},
+		"RFC 3339 with offset": {
+			input:        "2026-07-18T21:30:00+02:00",
+			expectedTime: time.Date(2026, 7, 18, 19, 30, 0, 0, time.UTC),
+		},
+		"time in the given timezone": {
+			input:        "2026-07-18 21:30",
+			expectedTime: time.Date(2026, 7, 18, 19, 30, 0, 0, time.UTC),
+		},
+		"relative time": {
+			input:        "15 minutes ago",
+			expectedTime: now.Add(-15 * time.Minute),
+		},
+		"relative duration": {
+			input:        "1h30m ago",
+			expectedTime: now.Add(-90 * time.Minute),
+		},
+		"unknown unit": {
+			input:         "3 weeks ago",
+			expectedError: "unknown time unit 'weeks'",
+		},
+		"invalid time": {
+			input:         "yesterday",
+			expectedError: "invalid restore time 'yesterday'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			restoreTime, err := ParseRestoreTime(context.Background(), test.input, now, paris)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, test.expectedTime.Equal(restoreTime), "expected %v, got %v", test.expectedTime, restoreTime)
+		})
+	}
+}
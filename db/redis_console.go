@@ -8,6 +8,8 @@ import (
 	"net"
 	"strings"
 
+	"github.com/kballard/go-shellquote"
+
 	"github.com/Scalingo/cli/apps"
 	"github.com/Scalingo/go-utils/errors/v3"
 )
@@ -16,12 +18,17 @@ type RedisConsoleOpts struct {
 	App          string
 	Size         string
 	VariableName string
+	Query        ConsoleQueryOpts
 }
 
 func RedisConsole(ctx context.Context, opts RedisConsoleOpts) error {
 	if opts.VariableName == "" {
 		opts.VariableName = "SCALINGO_REDIS"
 	}
+	err := opts.Query.validate(ctx, ConsoleFormatTable, ConsoleFormatCSV)
+	if err != nil {
+		return err
+	}
 	redisURL, _, password, err := dbURL(ctx, opts.App, opts.VariableName, []string{"redis", "rediss"})
 	if err != nil {
 		return errors.Wrapf(ctx, err, "resolve Redis URL from %s", opts.VariableName)
@@ -35,16 +42,20 @@ func RedisConsole(ctx context.Context, opts RedisConsoleOpts) error {
 	if err != nil {
 		return fmt.Errorf("%v has an invalid host", redisURL)
 	}
+	queryArgs, err := redisQueryArgs(ctx, opts.Query)
+	if err != nil {
+		return err
+	}
 
 	runOpts := apps.RunOpts{
 		DisplayCmd:    "redis-console " + strings.Split(host, ".")[0],
 		App:           opts.App,
-		Cmd:           []string{"dbclient-fetcher", "redis", "&&", "redis-cli", "-h", host, "-p", port, "-a", password},
+		Cmd:           append(opts.Query.fetchClient("redis"), append([]string{"redis-cli", "-h", host, "-p", port, "-a", password}, queryArgs...)...),
 		Size:          opts.Size,
 		StdinCopyFunc: redisStdinCopy,
 	}
 
-	err = apps.Run(ctx, runOpts)
+	err = runConsole(ctx, runOpts, opts.Query)
 	if err != nil {
 		return fmt.Errorf("fail to run Redis console: %v", err)
 	}
@@ -52,6 +63,32 @@ func RedisConsole(ctx context.Context, opts RedisConsoleOpts) error {
 	return nil
 }
 
+// redisQueryArgs returns the redis-cli arguments to execute the command
+// non-interactively. The command is split into words locally, like on a local
+// redis-cli, and each word is quoted so that the remote shell neither expands
+// nor interprets them.
+func redisQueryArgs(ctx context.Context, q ConsoleQueryOpts) ([]string, error) {
+	if q.interactive() {
+		return nil, nil
+	}
+
+	var args []string
+	if q.Format == ConsoleFormatCSV {
+		args = append(args, "--csv")
+	}
+	if q.File != "" {
+		return append(args, "<", q.uploadedFile()), nil
+	}
+	words, err := shellquote.Split(q.Command)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "split the Redis command into words")
+	}
+	for _, word := range words {
+		args = append(args, shellQuote(word))
+	}
+	return args, nil
+}
+
 func redisStdinCopy(dst io.Writer, src io.Reader) (int64, error) {
 	var written int64
 	buf := make([]byte, 2*1024)
@@ -7,16 +7,20 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
-	"golang.org/x/crypto/ssh"
+	humanize "github.com/dustin/go-humanize"
+	"github.com/olekukonko/tablewriter"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
 	netssh "github.com/Scalingo/cli/net/ssh"
+	"github.com/Scalingo/cli/signals"
 	"github.com/Scalingo/go-scalingo/v11"
 	"github.com/Scalingo/go-scalingo/v11/debug"
 	"github.com/Scalingo/go-utils/errors/v3"
@@ -34,7 +38,37 @@ type TunnelOpts struct {
 	Identity  string
 	Bind      string
 	Port      int
+	Socket    string
 	Reconnect bool
+
+	// KeepAliveInterval is the interval between two SSH keepalive requests. A
+	// zero value disables keepalives.
+	KeepAliveInterval time.Duration
+
+	// Targets are the forwardings to multiplex over a single SSH connection. If
+	// empty, a single forwarding is built from App, DBEnvVar, Bind and Port.
+	Targets []TunnelTarget
+}
+
+// TunnelTarget is a single forwarding of a database tunnel.
+type TunnelTarget struct {
+	App      string `yaml:"app"`
+	DBEnvVar string `yaml:"variable"`
+	Bind     string `yaml:"bind"`
+	Port     int    `yaml:"port"`
+	// Socket is the path of a Unix domain socket to bind instead of a TCP port.
+	Socket string `yaml:"socket"`
+}
+
+type tunnelForwarding struct {
+	target   TunnelTarget
+	dbURL    *url.URL
+	listener net.Listener
+
+	connections atomic.Int64
+	active      atomic.Int64
+	bytesSent   atomic.Int64
+	bytesRecv   atomic.Int64
 }
 
 func Tunnel(ctx context.Context, opts TunnelOpts) error {
@@ -47,113 +81,156 @@ func Tunnel(ctx context.Context, opts TunnelOpts) error {
 	if err != nil {
 		return errors.Wrapf(ctx, err, "fail to retrieve region information")
 	}
-	sshhost := region.SSH
 
-	environ, err := c.VariablesListWithoutAlias(ctx, opts.App)
-	if err != nil {
-		return errors.Wrapf(ctx, err, "list variables for app %s", opts.App)
+	targets := opts.Targets
+	if len(targets) == 0 {
+		targets = []TunnelTarget{{
+			App: opts.App, DBEnvVar: opts.DBEnvVar, Bind: opts.Bind, Port: opts.Port, Socket: opts.Socket,
+		}}
 	}
 
-	dbURLStr := dbEnvVarValue(opts.DBEnvVar, environ)
-	if dbURLStr == "" {
-		return errors.Newf(ctx, "no such environment variable: %s", opts.DBEnvVar)
-	}
+	environs := map[string]scalingo.Variables{}
+	forwardings := make([]*tunnelForwarding, 0, len(targets))
+	for _, target := range targets {
+		if target.App == "" {
+			target.App = opts.App
+		}
+		environ, ok := environs[target.App]
+		if !ok {
+			environ, err = c.VariablesListWithoutAlias(ctx, target.App)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "list variables for app %s", target.App)
+			}
+			environs[target.App] = environ
+		}
 
-	dbURL, err := url.Parse(dbURLStr)
-	if err != nil {
-		return errors.Wrapf(ctx, err, "invalid database 'URL': %s", dbURLStr)
+		dbURLStr := dbEnvVarValue(target.DBEnvVar, environ)
+		if dbURLStr == "" {
+			return errors.Newf(ctx, "no such environment variable: %s", target.DBEnvVar)
+		}
+
+		dbURL, err := url.Parse(dbURLStr)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "invalid database 'URL': %s", dbURLStr)
+		}
+		fmt.Fprintf(os.Stderr, "Building tunnel to %s\n", dbURL.Host)
+		forwardings = append(forwardings, &tunnelForwarding{target: target, dbURL: dbURL})
 	}
-	fmt.Fprintf(os.Stderr, "Building tunnel to %s\n", dbURL.Host)
 
-	// Just test the connection
-	client, _, err := netssh.Connect(ctx, netssh.ConnectOpts{
-		Host:     sshhost,
-		Identity: opts.Identity,
+	tunnelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sshClient := netssh.NewSharedClient(netssh.SharedClientOpts{
+		Host:              region.SSH,
+		Identity:          opts.Identity,
+		KeepAliveInterval: opts.KeepAliveInterval,
+		Reconnect:         opts.Reconnect,
 	})
+	err = sshClient.Connect(tunnelCtx)
 	if err != nil {
-		if err == netssh.ErrNoAuthSucceed {
+		if errors.Is(err, netssh.ErrNoAuthSucceed) {
 			return errors.Wrapf(ctx, err, "please use the flag '-i /path/to/private/key' to specify your private key")
 		}
 		return errors.Wrapf(ctx, err, "fail to connect to SSH server")
 	}
-	client.Close()
+	defer sshClient.Close()
 
-	if opts.Port == 0 {
-		opts.Port = defaultPort
-	}
-	if opts.Bind == "" {
-		opts.Bind = defaultBind
-	}
-
-	var tcpAddr *net.TCPAddr
-	var sock *net.TCPListener
-	for {
-		tcpAddr, err = net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:%d", opts.Bind, opts.Port))
-		if err != nil {
-			return errors.Wrap(ctx, err, "resolve local TCP address")
+	nextPort := defaultPort
+	for _, forwarding := range forwardings {
+		if forwarding.target.Socket != "" {
+			forwarding.listener, err = listenUnix(ctx, forwarding.target.Socket)
+			if err != nil {
+				return errors.Wrapf(ctx, err, "listen for %s", forwarding.target.DBEnvVar)
+			}
+			defer forwarding.listener.Close()
+			continue
 		}
 
-		sock, err = net.ListenTCP("tcp", tcpAddr)
-		if isAddrInUse(err) {
-			opts.Port++
-			continue
+		if forwarding.target.Port == 0 {
+			forwarding.target.Port = nextPort
+		}
+		if forwarding.target.Bind == "" {
+			forwarding.target.Bind = defaultBind
 		}
+
+		forwarding.listener, err = listenTCP(ctx, forwarding.target.Bind, &forwarding.target.Port)
 		if err != nil {
-			return errors.Wrap(ctx, err, "listen on local TCP socket")
+			return errors.Wrapf(ctx, err, "listen for %s", forwarding.target.DBEnvVar)
 		}
-		break
+		defer forwarding.listener.Close()
+		nextPort = forwarding.target.Port + 1
 	}
 
-	defer sock.Close()
-	fmt.Fprintln(os.Stderr, "You can access your database on:")
-	fmt.Printf("%v\n", sock.Addr())
+	if len(forwardings) == 1 {
+		fmt.Fprintln(os.Stderr, "You can access your database on:")
+		fmt.Printf("%v\n", forwardings[0].listener.Addr())
+	} else {
+		fmt.Fprintln(os.Stderr, "You can access your databases on:")
+		t := tablewriter.NewWriter(os.Stdout)
+		t.Header([]string{"Local Address", "App", "Variable", "Remote Host"})
+		for _, forwarding := range forwardings {
+			t.Append([]string{
+				forwarding.listener.Addr().String(),
+				forwarding.target.App,
+				forwarding.target.DBEnvVar,
+				forwarding.dbURL.Host,
+			})
+		}
+		_ = t.Render()
+	}
 
 	go startIDGenerator()
-	errs := make(chan error)
+
+	signals.CatchQuitSignals = false
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	errs := make(chan error, len(forwardings))
+	for _, forwarding := range forwardings {
+		go func() {
+			errs <- acceptTunnelConnections(tunnelCtx, sshClient, forwarding, opts.Reconnect)
+		}()
+	}
+
+	select {
+	case err := <-errs:
+		return errors.Wrap(ctx, err, "handle database tunnel connection")
+	case err := <-sshClient.Failures():
+		return errors.Wrap(ctx, err, "SSH connection lost")
+	case <-interrupt:
+		fmt.Fprintln(os.Stderr)
+		printTunnelStats(forwardings)
+		return nil
+	}
+}
+
+func acceptTunnelConnections(ctx context.Context, sshClient *netssh.SharedClient, forwarding *tunnelForwarding, reconnect bool) error {
+	errs := make(chan error, 1)
 	for {
 		select {
 		case err := <-errs:
-			return errors.Wrap(ctx, err, "handle database tunnel connection")
+			return err
 		default:
 		}
 
-		debug.Println("Waiting local connection request")
-		connToTunnel, err := sock.AcceptTCP()
+		debug.Println("Waiting local connection request on", forwarding.listener.Addr())
+		connToTunnel, err := forwarding.listener.Accept()
 		if err != nil {
-			return errors.Wrap(ctx, err, "accept local TCP connection")
+			return errors.Wrap(ctx, err, "accept local connection")
 		}
 		debug.Println("New local connection")
 
 		go func() {
-			for {
-				var client *ssh.Client
-				retryConnect := true
-				for retryConnect {
-					// Do not reuse key since the connection to the SSH agent might be broken
-					client, _, err = netssh.Connect(ctx, netssh.ConnectOpts{
-						Host:     sshhost,
-						Identity: opts.Identity,
-					})
-					if err != nil {
-						fmt.Println("Fail to reconnect, waiting 10 seconds...")
-						time.Sleep(10 * time.Second)
-					} else {
-						retryConnect = false
-					}
-				}
-
-				err = handleConnToTunnel(ctx, client, dbURL, connToTunnel, errs)
-				if err != nil {
-					debug.Println("Error happened in tunnel", err)
-					if !opts.Reconnect {
-						errs <- err
-						return
+			err := handleConnToTunnel(ctx, sshClient, forwarding, connToTunnel)
+			if err != nil {
+				debug.Println("Error happened in tunnel", err)
+				if !reconnect {
+					select {
+					case errs <- err:
+					default:
 					}
 				}
-				// If err is nil, the connection has been closed normally, close the routine
-				if err == nil {
-					return
-				}
 			}
 		}()
 	}
@@ -171,24 +248,30 @@ func dbEnvVarValue(dbEnvVar string, environ scalingo.Variables) string {
 	return ""
 }
 
-func handleConnToTunnel(ctx context.Context, sshClient *ssh.Client, dbURL *url.URL, sock net.Conn, errs chan error) error {
+func handleConnToTunnel(ctx context.Context, sshClient *netssh.SharedClient, forwarding *tunnelForwarding, sock net.Conn) error {
+	defer sock.Close()
+
 	connID := <-connIDGenerator
-	fmt.Printf("Connect to %s [%v]\n", dbURL.Host, connID)
-	conn, err := sshClient.Dial("tcp", dbURL.Host)
+	host := forwarding.dbURL.Host
+	fmt.Printf("Connect to %s [%v]\n", host, connID)
+
+	conn, err := sshClient.Dial(ctx, host)
 	if err != nil {
-		if err != stdio.EOF {
-			errs <- err
-		}
-		return nil
+		return errors.Wrapf(ctx, err, "dial %s", host)
 	}
-	debug.Println("Connected to", dbURL.Host, connID)
+	debug.Println("Connected to", host, connID)
+
+	forwarding.connections.Add(1)
+	forwarding.active.Add(1)
+	defer forwarding.active.Add(-1)
 
 	wg := &sync.WaitGroup{}
 	wg.Add(2)
 
 	go func() {
 		debug.Println("Pipe DB -> Local ON")
-		_, remoteErr := stdio.Copy(sock, conn)
+		n, remoteErr := stdio.Copy(sock, conn)
+		forwarding.bytesRecv.Add(n)
 		debug.Println("Pipe DB -> Local OFF", remoteErr)
 		sock.Close()
 		wg.Done()
@@ -196,7 +279,9 @@ func handleConnToTunnel(ctx context.Context, sshClient *ssh.Client, dbURL *url.U
 
 	go func() {
 		debug.Println("Local -> DB ON")
-		_, err = io.CopyWithTimeout(2*time.Second)(conn, sock)
+		var n int64
+		n, err = io.CopyWithTimeout(2*time.Second)(conn, sock)
+		forwarding.bytesSent.Add(n)
 		debug.Println("Local -> DB OFF", err)
 		conn.Close()
 		wg.Done()
@@ -208,7 +293,7 @@ func handleConnToTunnel(ctx context.Context, sshClient *ssh.Client, dbURL *url.U
 	// Connection timeout
 	if err != nil && strings.Contains(err.Error(), "use of closed network") {
 		// If the connection has been closed by the CLIENT, we must stop here and return a nil error
-		// If the connection has been closed by the SERVER, we must return a errTimeout and retry the connection
+		// If the connection has been closed by the SERVER, we must return a errTimeout
 		clientClosedConnectionTest := fmt.Sprintf("%s->%s: use of closed network connection", sock.LocalAddr(), sock.RemoteAddr()) // Golang error checking 101 <3
 		if strings.Contains(err.Error(), clientClosedConnectionTest) {
 			return nil
@@ -219,6 +304,68 @@ func handleConnToTunnel(ctx context.Context, sshClient *ssh.Client, dbURL *url.U
 	return nil
 }
 
+func printTunnelStats(forwardings []*tunnelForwarding) {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Local Address", "Remote Host", "Connections", "Sent", "Received"})
+	for _, forwarding := range forwardings {
+		t.Append([]string{
+			forwarding.listener.Addr().String(),
+			forwarding.dbURL.Host,
+			fmt.Sprintf("%d", forwarding.connections.Load()),
+			humanize.Bytes(uint64(forwarding.bytesSent.Load())),
+			humanize.Bytes(uint64(forwarding.bytesRecv.Load())),
+		})
+	}
+	_ = t.Render()
+}
+
+// listenTCP listens on the first available port starting from *port, and
+// updates it with the port actually bound.
+func listenTCP(ctx context.Context, bind string, port *int) (*net.TCPListener, error) {
+	for {
+		tcpAddr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("%s:%d", bind, *port))
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "resolve local TCP address")
+		}
+
+		sock, err := net.ListenTCP("tcp", tcpAddr)
+		if isAddrInUse(err) {
+			*port++
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(ctx, err, "listen on local TCP socket")
+		}
+		return sock, nil
+	}
+}
+
+// listenUnix binds a Unix domain socket at path. A stale socket file left by a
+// previous tunnel is removed, but a socket which is still in use is kept.
+func listenUnix(ctx context.Context, path string) (net.Listener, error) {
+	stat, err := os.Stat(path)
+	if err == nil {
+		if stat.Mode()&os.ModeSocket == 0 {
+			return nil, errors.Newf(ctx, "%s already exists and is not a socket", path)
+		}
+		conn, err := net.Dial("unix", path)
+		if err == nil {
+			conn.Close()
+			return nil, errors.Newf(ctx, "%s is already used by another process", path)
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "remove stale socket %s", path)
+		}
+	}
+
+	sock, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "listen on local Unix socket")
+	}
+	return sock, nil
+}
+
 func startIDGenerator() {
 	for i := 1; ; i++ {
 		connIDGenerator <- i
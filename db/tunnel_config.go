@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+type tunnelsConfig struct {
+	Tunnels []TunnelTarget `yaml:"tunnels"`
+}
+
+// ReadTunnelTargets reads the forwardings of a database tunnel from a YAML
+// configuration file:
+//
+//	tunnels:
+//	  - app: my-app
+//	    variable: SCALINGO_POSTGRESQL_URL
+//	    port: 10000
+//	  - variable: SCALINGO_REDIS_URL
+//	    bind: 0.0.0.0
+func ReadTunnelTargets(ctx context.Context, path string) ([]TunnelTarget, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read tunnels configuration %s", path)
+	}
+
+	var cfg tunnelsConfig
+	err = yaml.Unmarshal(content, &cfg)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse tunnels configuration %s", path)
+	}
+	if len(cfg.Tunnels) == 0 {
+		return nil, errors.Newf(ctx, "no tunnel defined in %s", path)
+	}
+	for i, target := range cfg.Tunnels {
+		if target.DBEnvVar == "" {
+			return nil, errors.Newf(ctx, "tunnel #%d of %s has no variable", i+1, path)
+		}
+	}
+	return cfg.Tunnels, nil
+}
+
+// ParseTunnelTarget parses a forwarding given as '[app/]VARIABLE[:port]'. A
+// connection URL is also accepted as is.
+func ParseTunnelTarget(ctx context.Context, value string) (TunnelTarget, error) {
+	if strings.Contains(value, "://") {
+		return TunnelTarget{DBEnvVar: value}, nil
+	}
+
+	var target TunnelTarget
+	variable := value
+	if app, v, found := strings.Cut(value, "/"); found {
+		if app == "" {
+			return TunnelTarget{}, errors.Newf(ctx, "invalid tunnel '%s', format is '[app/]VARIABLE[:port]'", value)
+		}
+		target.App = app
+		variable = v
+	}
+
+	variable, port, found := strings.Cut(variable, ":")
+	if found {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 || p > 65535 {
+			return TunnelTarget{}, errors.Newf(ctx, "invalid port '%s' in '%s'", port, value)
+		}
+		target.Port = p
+	}
+
+	if variable == "" {
+		return TunnelTarget{}, errors.Newf(ctx, "invalid tunnel '%s', format is '[app/]VARIABLE[:port]'", value)
+	}
+	target.DBEnvVar = variable
+	return target, nil
+}
@@ -3,15 +3,24 @@ package users
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
 	"github.com/Scalingo/go-scalingo/v11"
 	"github.com/Scalingo/go-utils/errors/v3"
 	"github.com/Scalingo/gopassword"
 )
 
-func CreateUser(ctx context.Context, app, addonUUID, username string, readonly bool) error {
+type CreateUserOpts struct {
+	ReadOnly bool
+	// TTL is the time to live of a temporary user, deleted by Reap once
+	// expired. The password of a temporary user is always generated.
+	TTL time.Duration
+}
+
+func CreateUser(ctx context.Context, app, addonUUID, username string, opts CreateUserOpts) error {
 	isSupported, err := doesDatabaseHandleUserManagement(ctx, app, addonUUID)
 	if err != nil {
 		return errors.Wrap(ctx, err, "get user management information")
@@ -48,10 +57,13 @@ func CreateUser(ctx context.Context, app, addonUUID, username string, readonly b
 		return errors.New(ctx, fmt.Sprintf("User \"%s\" already exists", username))
 	}
 
-	password, confirmedPassword, err := askForPasswordWithRetry(ctx, 3)
-	if err != nil {
-		io.Error(err)
-		return nil
+	var password, confirmedPassword string
+	if opts.TTL == 0 {
+		password, confirmedPassword, err = askForPasswordWithRetry(ctx, 3)
+		if err != nil {
+			io.Error(err)
+			return nil
+		}
 	}
 
 	isPasswordGenerated := false
@@ -66,7 +78,7 @@ func CreateUser(ctx context.Context, app, addonUUID, username string, readonly b
 		Name:                 username,
 		Password:             password,
 		PasswordConfirmation: confirmedPassword,
-		ReadOnly:             readonly,
+		ReadOnly:             opts.ReadOnly,
 	}
 	databaseUsers, err := c.DatabaseCreateUser(ctx, app, addonUUID, user)
 	if err != nil {
@@ -75,9 +87,28 @@ func CreateUser(ctx context.Context, app, addonUUID, username string, readonly b
 
 	if isPasswordGenerated {
 		fmt.Printf("User \"%s\" created with password \"%s\".\n", databaseUsers.Name, password)
-		return nil
+		u, err := connectionURL(ctx, c, app, addonUUID, databaseUsers.Name, password)
+		if err != nil {
+			io.Warningf("Fail to build the connection URL: %v\n", err)
+		} else {
+			fmt.Printf("Connection URL: %s\n", u)
+		}
+	} else {
+		fmt.Printf("User \"%s\" created.\n", databaseUsers.Name)
+	}
+
+	if opts.TTL > 0 {
+		now := time.Now()
+		expiresAt := now.Add(opts.TTL)
+		err := recordTemporaryUser(ctx, temporaryUsersPath(), temporaryUser{
+			App: app, AddonID: addonUUID, Username: databaseUsers.Name, CreatedAt: now, ExpiresAt: expiresAt,
+		})
+		if err != nil {
+			io.Warningf("User \"%s\" will not be deleted by database-users-reap: %v\n", databaseUsers.Name, err)
+		} else {
+			fmt.Printf("User \"%s\" expires at %s, delete it with database-users-reap.\n", databaseUsers.Name, expiresAt.Format(utils.TimeFormat))
+		}
 	}
 
-	fmt.Printf("User \"%s\" created.\n", databaseUsers.Name)
 	return nil
 }
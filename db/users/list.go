@@ -4,11 +4,13 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
 	"github.com/Scalingo/go-utils/errors/v3"
 )
 
@@ -38,6 +40,22 @@ func List(ctx context.Context, app, addonUUID string) error {
 		header = append(header, "Password Encryption")
 	}
 
+	// The expiry of the temporary users is only known by the CLI which created
+	// them
+	temporaryUsers, err := readTemporaryUsers(ctx, temporaryUsersPath())
+	if err != nil {
+		io.Warningf("Fail to read the expiry of the temporary users: %v\n", err)
+	}
+	expiries := map[string]time.Time{}
+	for _, user := range temporaryUsers {
+		if user.App == app && user.AddonID == addonUUID {
+			expiries[user.Username] = user.ExpiresAt
+		}
+	}
+	if len(expiries) > 0 {
+		header = append(header, "Expires At")
+	}
+
 	t := tablewriter.NewWriter(os.Stdout)
 	t.Header(header)
 
@@ -50,6 +68,13 @@ func List(ctx context.Context, app, addonUUID string) error {
 		if user.DbmsAttributes != nil {
 			line = append(line, user.DbmsAttributes.PasswordEncryption)
 		}
+		if len(expiries) > 0 {
+			expiresAt := ""
+			if expiry, ok := expiries[user.Name]; ok {
+				expiresAt = expiry.Format(utils.TimeFormat)
+			}
+			line = append(line, expiresAt)
+		}
 		t.Append(line)
 	}
 	t.Render()
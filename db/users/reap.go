@@ -0,0 +1,110 @@
+package users
+
+import (
+	"context"
+	"time"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+type ReapOpts struct {
+	// DryRun only displays the expired users
+	DryRun bool
+}
+
+// Reap deletes the expired temporary users created with a time to live. The
+// users already deleted from their database are forgotten.
+func Reap(ctx context.Context, opts ReapOpts) error {
+	path := temporaryUsersPath()
+	users, err := readTemporaryUsers(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var remaining []temporaryUser
+	var expired []temporaryUser
+	for _, user := range users {
+		if user.expired(now) {
+			expired = append(expired, user)
+		} else {
+			remaining = append(remaining, user)
+		}
+	}
+	if len(expired) == 0 {
+		io.Statusf("No expired database user (%d temporary users)\n", len(users))
+		return nil
+	}
+	if opts.DryRun {
+		for _, user := range expired {
+			io.Infof("User %s of %s (%s) expired at %s\n", user.Username, user.App, user.AddonID, user.ExpiresAt.Format(utils.TimeFormat))
+		}
+		return nil
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	// The consent is checked once per app, without prompting when run by a
+	// cron job
+	consentErrors := map[string]error{}
+	for _, user := range expired {
+		if _, ok := consentErrors[user.App]; !ok {
+			consentErrors[user.App] = utils.EnsureConsent(ctx, user.App, utils.ConsentTypeDBs)
+		}
+	}
+
+	failures := 0
+	for _, user := range expired {
+		err := consentErrors[user.App]
+		if err == nil {
+			err = reapUser(ctx, c, user)
+		}
+		if err != nil {
+			io.Errorf("Fail to delete user %s of %s (%s): %v\n", user.Username, user.App, user.AddonID, err)
+			// The deletion is tried again on the next run
+			remaining = append(remaining, user)
+			failures++
+		}
+	}
+
+	err = writeTemporaryUsers(ctx, path, remaining)
+	if err != nil {
+		return err
+	}
+	if failures > 0 {
+		return errors.Newf(ctx, "%d of %d expired users could not be deleted", failures, len(expired))
+	}
+	return nil
+}
+
+func reapUser(ctx context.Context, c *scalingo.Client, user temporaryUser) error {
+	databaseUsers, err := c.DatabaseListUsers(ctx, user.App, user.AddonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list the database's users")
+	}
+	exists := false
+	for _, databaseUser := range databaseUsers {
+		if databaseUser.Name == user.Username {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		io.Statusf("User %s of %s was already deleted\n", user.Username, user.App)
+		return nil
+	}
+
+	err = c.DatabaseDeleteUser(ctx, user.App, user.AddonID, user.Username)
+	if err != nil {
+		return errors.Wrap(ctx, err, "delete user")
+	}
+	io.Statusf("User %s of %s has been deleted, it expired at %s\n", user.Username, user.App, user.ExpiresAt.Format(utils.TimeFormat))
+	return nil
+}
@@ -0,0 +1,121 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// temporaryUsersFile is the file of the configuration directory where the
+// expiry of the temporary users is recorded
+const temporaryUsersFile = "database_users.json"
+
+// connectionURLVariables are the environment variables containing the
+// connection URL of the databases, by addon provider ID
+var connectionURLVariables = map[string]string{
+	"postgresql": "SCALINGO_POSTGRESQL_URL",
+	"mysql":      "SCALINGO_MYSQL_URL",
+	"mongodb":    "SCALINGO_MONGO_URL",
+	"influxdb":   "SCALINGO_INFLUX_URL",
+}
+
+// temporaryUser is a database user created with a time to live, deleted by
+// Reap once expired
+type temporaryUser struct {
+	App       string    `json:"app"`
+	AddonID   string    `json:"addon_id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (u temporaryUser) expired(now time.Time) bool {
+	return !now.Before(u.ExpiresAt)
+}
+
+func temporaryUsersPath() string {
+	return filepath.Join(config.C.ConfigDir, temporaryUsersFile)
+}
+
+func readTemporaryUsers(ctx context.Context, path string) ([]temporaryUser, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "read temporary users")
+	}
+	var users []temporaryUser
+	err = json.Unmarshal(content, &users)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "invalid temporary users file %s", path)
+	}
+	return users, nil
+}
+
+func writeTemporaryUsers(ctx context.Context, path string, users []temporaryUser) error {
+	content, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return errors.Wrap(ctx, err, "encode temporary users")
+	}
+	// The file only contains the user names, but the users are sensitive
+	err = os.WriteFile(path, content, 0600)
+	if err != nil {
+		return errors.Wrap(ctx, err, "write temporary users")
+	}
+	return nil
+}
+
+// recordTemporaryUser records the expiry of a user, replacing a previous
+// record of a user with the same name on the same database
+func recordTemporaryUser(ctx context.Context, path string, user temporaryUser) error {
+	users, err := readTemporaryUsers(ctx, path)
+	if err != nil {
+		return err
+	}
+	users = slices.DeleteFunc(users, func(u temporaryUser) bool {
+		return u.App == user.App && u.AddonID == user.AddonID && u.Username == user.Username
+	})
+	return writeTemporaryUsers(ctx, path, append(users, user))
+}
+
+// connectionURL returns the connection URL of the database with the
+// credentials of the given user
+func connectionURL(ctx context.Context, c *scalingo.Client, app, addonUUID, username, password string) (string, error) {
+	addon, err := c.AddonShow(ctx, app, addonUUID)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "get addon")
+	}
+	if addon.AddonProvider == nil {
+		return "", errors.Newf(ctx, "unknown provider for addon %s", addonUUID)
+	}
+	variableName, ok := connectionURLVariables[addon.AddonProvider.ID]
+	if !ok {
+		return "", errors.Newf(ctx, "unknown connection URL variable for %s", addon.AddonProvider.Name)
+	}
+
+	variables, err := c.VariablesListWithoutAlias(ctx, app)
+	if err != nil {
+		return "", errors.Wrapf(ctx, err, "list variables for app %s", app)
+	}
+	for _, variable := range variables {
+		if variable.Name != variableName {
+			continue
+		}
+		u, err := url.Parse(variable.Value)
+		if err != nil {
+			return "", errors.Wrapf(ctx, err, "invalid %s", variableName)
+		}
+		u.User = url.UserPassword(username, password)
+		return u.String(), nil
+	}
+	return "", errors.Newf(ctx, "%s not found in the environment of %s", variableName, app)
+}
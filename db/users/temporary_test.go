@@ -0,0 +1,37 @@
+package users
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_recordTemporaryUser(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), temporaryUsersFile)
+	now := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	users, err := readTemporaryUsers(ctx, path)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+
+	analyst := temporaryUser{App: "my-app", AddonID: "ad-1", Username: "analyst", CreatedAt: now, ExpiresAt: now.Add(8 * time.Hour)}
+	auditor := temporaryUser{App: "my-app", AddonID: "ad-1", Username: "auditor", CreatedAt: now, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, recordTemporaryUser(ctx, path, analyst))
+	require.NoError(t, recordTemporaryUser(ctx, path, auditor))
+
+	// The user is created again with another time to live
+	analyst.ExpiresAt = now.Add(2 * time.Hour)
+	require.NoError(t, recordTemporaryUser(ctx, path, analyst))
+
+	users, err = readTemporaryUsers(ctx, path)
+	require.NoError(t, err)
+	assert.Equal(t, []temporaryUser{auditor, analyst}, users)
+
+	assert.False(t, analyst.expired(now.Add(time.Hour)))
+	assert.True(t, analyst.expired(now.Add(2*time.Hour)))
+}
@@ -0,0 +1,169 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// maxUpgradePathLength protects against loops in the upgrade path
+const maxUpgradePathLength = 10
+
+// databaseVersion is the version information of a database addon
+type databaseVersion struct {
+	App          string
+	AddonID      string
+	ProviderID   string
+	DatabaseType string
+	Current      scalingo.DatabaseTypeVersion
+	Readable     string
+	Plugins      []string
+	// UpgradePath are the versions to go through to upgrade to the latest
+	// version
+	UpgradePath []scalingo.DatabaseTypeVersion
+	EndOfLife   time.Time
+	EOLStatus   eolStatus
+}
+
+// ListVersions displays the version, the plugins, the upgrade path and the
+// end of life status of all the database addons of the user. The end of life
+// is advisory, it comes from a table of the upstream announcements embedded in
+// the CLI.
+func ListVersions(ctx context.Context, projectSlug string) error {
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	databaseAddons, err := ListDatabaseAddons(ctx, c, projectSlug)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list database addons")
+	}
+	if len(databaseAddons) == 0 {
+		io.Status("No database addon found")
+		return nil
+	}
+
+	now := time.Now()
+	versions := make([]databaseVersion, len(databaseAddons))
+	errs := make([]error, len(databaseAddons))
+	ForEachDatabaseAddon(databaseAddons, func(i int, databaseAddon DatabaseAddon) {
+		versions[i], errs[i] = getDatabaseVersion(ctx, c, databaseAddon.App.Name, databaseAddon.Addon, now)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// The databases which must be upgraded first are displayed first
+	eolOrder := []eolStatus{eolStatusReached, eolStatusSoon, eolStatusSupported, eolStatusUnknown}
+	slices.SortStableFunc(versions, func(a, b databaseVersion) int {
+		return slices.Index(eolOrder, a.EOLStatus) - slices.Index(eolOrder, b.EOLStatus)
+	})
+
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"App", "Addon", "Version", "Plugins", "Upgrade Path", "End of Life (upstream)"})
+	upgradable := 0
+	for _, version := range versions {
+		upgradePath := "up to date"
+		if len(version.UpgradePath) > 0 {
+			upgradable++
+			upgradePath = formatUpgradePath(version.UpgradePath)
+		}
+		_ = t.Append([]string{
+			version.App,
+			fmt.Sprintf("%s (%s)", version.DatabaseType, version.AddonID),
+			version.Readable,
+			strings.Join(version.Plugins, "\n"),
+			upgradePath,
+			formatEndOfLife(version.EndOfLife, version.EOLStatus),
+		})
+	}
+	_ = t.Render()
+
+	if upgradable > 0 {
+		io.Infof("%d databases can be upgraded from the dashboard of the databases\n", upgradable)
+	}
+	return nil
+}
+
+func getDatabaseVersion(ctx context.Context, c *scalingo.Client, app string, addon *scalingo.Addon, now time.Time) (databaseVersion, error) {
+	database, err := c.DatabaseShow(ctx, app, addon.ID)
+	if err != nil {
+		return databaseVersion{}, errors.Wrapf(ctx, err, "get database %s of %s", addon.ID, app)
+	}
+	current, err := c.DatabaseTypeVersion(ctx, app, addon.ID, database.VersionID)
+	if err != nil {
+		return databaseVersion{}, errors.Wrapf(ctx, err, "get version of database %s of %s", addon.ID, app)
+	}
+
+	version := databaseVersion{
+		App:          app,
+		AddonID:      addon.ID,
+		ProviderID:   addon.AddonProvider.ID,
+		DatabaseType: addon.AddonProvider.Name,
+		Current:      current,
+		Readable:     database.ReadableVersion,
+	}
+	if version.Readable == "" {
+		version.Readable = formatVersion(current)
+	}
+
+	for _, plugin := range current.AllowedPlugins {
+		enabled := slices.ContainsFunc(database.Features, func(feature scalingo.DatabaseFeature) bool {
+			return feature.Name == plugin.FeatureName
+		})
+		if enabled {
+			version.Plugins = append(version.Plugins, plugin.DisplayName)
+		}
+	}
+
+	next := current.NextUpgrade
+	for next != nil && len(version.UpgradePath) < maxUpgradePathLength {
+		version.UpgradePath = append(version.UpgradePath, *next)
+		nextVersion, err := c.DatabaseTypeVersion(ctx, app, addon.ID, next.ID)
+		if err != nil {
+			return databaseVersion{}, errors.Wrapf(ctx, err, "get version %s", next.ID)
+		}
+		next = nextVersion.NextUpgrade
+	}
+
+	version.EndOfLife, version.EOLStatus = endOfLife(version.ProviderID, current.Major, current.Minor, now)
+	return version, nil
+}
+
+func formatVersion(version scalingo.DatabaseTypeVersion) string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+func formatUpgradePath(path []scalingo.DatabaseTypeVersion) string {
+	versions := make([]string, 0, len(path))
+	for _, version := range path {
+		versions = append(versions, formatVersion(version))
+	}
+	return strings.Join(versions, " → ")
+}
+
+func formatEndOfLife(eol time.Time, status eolStatus) string {
+	switch status {
+	case eolStatusUnknown:
+		return string(status)
+	case eolStatusReached:
+		return io.BoldRed(fmt.Sprintf("%s (%s)", status, eol.Format("2006-01")))
+	case eolStatusSoon:
+		return io.Yellow(fmt.Sprintf("%s (%s)", status, eol.Format("2006-01")))
+	default:
+		return eol.Format("2006-01")
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// eolWarningPeriod is the period before the end of life of a version during
+// which an upgrade should be planned
+const eolWarningPeriod = 180 * 24 * time.Hour
+
+type eolStatus string
+
+const (
+	eolStatusSupported eolStatus = "supported"
+	eolStatusSoon      eolStatus = "end of life soon"
+	eolStatusReached   eolStatus = "end of life"
+	eolStatusUnknown   eolStatus = "unknown"
+)
+
+// upstreamEndOfLife are the months when the upstream projects stop supporting
+// a version of the database engines, by addon provider ID and by version
+// (major or major.minor). The database API doesn't expose the end of life of
+// the versions: this table is advisory and must be updated with the upstream
+// announcements, the versions missing from it are reported as unknown.
+var upstreamEndOfLife = map[string]map[string]string{
+	postgreSQLProviderID: {
+		"10": "2022-11",
+		"11": "2023-11",
+		"12": "2024-11",
+		"13": "2025-11",
+		"14": "2026-11",
+		"15": "2027-11",
+		"16": "2028-11",
+		"17": "2029-11",
+	},
+	mySQLProviderID: {
+		"5.7": "2023-10",
+		"8.0": "2026-04",
+		"8.4": "2032-04",
+	},
+	mongoDBProviderID: {
+		"4.0": "2022-04",
+		"4.2": "2023-04",
+		"4.4": "2024-02",
+		"5.0": "2024-10",
+		"6.0": "2025-07",
+		"7.0": "2027-08",
+	},
+}
+
+// endOfLife returns the end of life of a version of a database engine, and
+// its status at the given time.
+func endOfLife(providerID string, major, minor int, now time.Time) (time.Time, eolStatus) {
+	versions, ok := upstreamEndOfLife[strings.ToLower(providerID)]
+	if !ok {
+		return time.Time{}, eolStatusUnknown
+	}
+	month, ok := versions[fmt.Sprintf("%d.%d", major, minor)]
+	if !ok {
+		month, ok = versions[fmt.Sprintf("%d", major)]
+	}
+	if !ok {
+		return time.Time{}, eolStatusUnknown
+	}
+
+	eol, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, eolStatusUnknown
+	}
+	switch {
+	case !now.Before(eol):
+		return eol, eolStatusReached
+	case now.Add(eolWarningPeriod).After(eol):
+		return eol, eolStatusSoon
+	default:
+		return eol, eolStatusSupported
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndOfLife(t *testing.T) {
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		providerID     string
+		major, minor   int
+		expectedEOL    time.Time
+		expectedStatus eolStatus
+	}{
+		"PostgreSQL major version reached end of life": {
+			providerID: "postgresql", major: 13, minor: 18,
+			expectedEOL:    time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: eolStatusReached,
+		},
+		"MySQL version close to end of life": {
+			providerID: "mysql", major: 8, minor: 0,
+			expectedEOL:    time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: eolStatusSoon,
+		},
+		"PostgreSQL supported version": {
+			providerID: "postgresql", major: 17, minor: 2,
+			expectedEOL:    time.Date(2029, 11, 1, 0, 0, 0, 0, time.UTC),
+			expectedStatus: eolStatusSupported,
+		},
+		"unknown MongoDB version": {
+			providerID: "mongodb", major: 8, minor: 0,
+			expectedStatus: eolStatusUnknown,
+		},
+		"unknown engine": {
+			providerID: "redis", major: 7, minor: 2,
+			expectedStatus: eolStatusUnknown,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			eol, status := endOfLife(test.providerID, test.major, test.minor, now)
+			assert.Equal(t, test.expectedStatus, status)
+			assert.Equal(t, test.expectedEOL, eol)
+		})
+	}
+}
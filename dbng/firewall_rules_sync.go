@@ -0,0 +1,289 @@
+package dbng
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	stdio "io"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	DefaultMyIPEndpoint = "https://api.ipify.org"
+	myIPLabel           = "My IP"
+)
+
+type FirewallRulesSyncOpts struct {
+	// File lists the allowed CIDRs, one per line followed by an optional label
+	File   string
+	DryRun bool
+	// AddMyIP adds the public IP of the caller to the allowed CIDRs
+	AddMyIP bool
+	// MyIPEndpoint is the URL returning the public IP of the caller
+	MyIPEndpoint string
+	// AllowEmpty allows a file without any CIDR, removing all the custom rules
+	AllowEmpty bool
+	// Yes skips the confirmation before removing rules
+	Yes bool
+}
+
+// desiredFirewallRule is a custom range allowed by the rules file
+type desiredFirewallRule struct {
+	CIDR  string
+	Label string
+}
+
+type firewallRulesPlan struct {
+	ToAdd     []desiredFirewallRule
+	ToRemove  []scalingo.FirewallRule
+	Unchanged []scalingo.FirewallRule
+}
+
+// FirewallRulesSync makes the custom range rules of the database match the
+// CIDRs of the rules file. The managed range rules are left untouched. The
+// rules are added before the other ones are removed so that the allowed
+// clients keep their access during the sync.
+func FirewallRulesSync(ctx context.Context, databaseID, addonID string, opts FirewallRulesSyncOpts) error {
+	desired, err := readFirewallRulesFile(ctx, opts.File)
+	if err != nil {
+		return err
+	}
+	if opts.AddMyIP {
+		endpoint := opts.MyIPEndpoint
+		if endpoint == "" {
+			endpoint = DefaultMyIPEndpoint
+		}
+		myIP, err := fetchMyIP(ctx, endpoint)
+		if err != nil {
+			return err
+		}
+		io.Infof("Your public IP is %s\n", myIP.Addr())
+		desired = addFirewallRule(desired, desiredFirewallRule{CIDR: myIP.String(), Label: myIPLabel})
+	}
+
+	if len(desired) == 0 && !opts.AllowEmpty {
+		return errors.Newf(ctx, "%s doesn't allow any CIDR, all the custom rules would be removed, use --allow-empty to confirm", opts.File)
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+	rules, err := c.Preview().FirewallRulesList(ctx, databaseID, addonID)
+	if err != nil {
+		return errors.Wrap(ctx, err, "list firewall rules")
+	}
+
+	plan := planFirewallRules(rules, desired)
+	if len(plan.ToAdd) == 0 && len(plan.ToRemove) == 0 {
+		io.Statusf("The firewall rules of database '%s' are already in sync (%d custom rules).\n", databaseID, len(plan.Unchanged))
+		return nil
+	}
+	renderFirewallRulesPlan(plan)
+	if opts.DryRun {
+		io.Infof("Dry run: %d rules would be added and %d removed\n", len(plan.ToAdd), len(plan.ToRemove))
+		return nil
+	}
+	if len(plan.ToRemove) > 0 && !opts.Yes && !utils.Confirm(fmt.Sprintf("Remove %d firewall rules of database '%s'? The clients they allow will lose their access.", len(plan.ToRemove), databaseID)) {
+		return errors.New(ctx, "sync not confirmed, aborting")
+	}
+
+	failures := 0
+	for _, rule := range plan.ToAdd {
+		_, err := c.Preview().FirewallRulesCreate(ctx, databaseID, addonID, scalingo.FirewallRuleCreateParams{
+			Type:  scalingo.FirewallRuleTypeCustomRange,
+			CIDR:  rule.CIDR,
+			Label: rule.Label,
+		})
+		if err != nil {
+			io.Errorf("Fail to add the firewall rule for %s: %v\n", rule.CIDR, err)
+			failures++
+			continue
+		}
+		io.Statusf("Firewall rule for %s has been added.\n", rule.CIDR)
+	}
+	if failures > 0 {
+		// Removing the other rules could leave the allowed clients without access
+		return errors.Newf(ctx, "%d firewall rules could not be added, no rule has been removed", failures)
+	}
+
+	for _, rule := range plan.ToRemove {
+		err := c.Preview().FirewallRulesDestroy(ctx, databaseID, addonID, rule.ID)
+		if err != nil {
+			io.Errorf("Fail to remove the firewall rule '%s' for %s: %v\n", rule.ID, rule.CIDR, err)
+			failures++
+			continue
+		}
+		io.Statusf("Firewall rule '%s' for %s has been removed.\n", rule.ID, rule.CIDR)
+	}
+	io.Warning("Expect some delay for the firewall rules to be applied.")
+	if failures > 0 {
+		return errors.Newf(ctx, "%d firewall rules could not be removed", failures)
+	}
+	return nil
+}
+
+func readFirewallRulesFile(ctx context.Context, path string) ([]desiredFirewallRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "open firewall rules file")
+	}
+	defer f.Close()
+
+	rules, err := parseFirewallRules(ctx, f)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "parse %s", path)
+	}
+	return rules, nil
+}
+
+// parseFirewallRules parses lines formatted as "<CIDR or IP> [label]".
+// Blank lines and lines starting with '#' are ignored.
+func parseFirewallRules(ctx context.Context, r stdio.Reader) ([]desiredFirewallRule, error) {
+	var rules []desiredFirewallRule
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cidr, label, _ := strings.Cut(line, " ")
+		prefix, err := parseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Newf(ctx, "line %d: invalid CIDR '%s'", lineNumber, cidr)
+		}
+		if previousLine, ok := seen[prefix.String()]; ok {
+			return nil, errors.Newf(ctx, "line %d: %s is already allowed on line %d", lineNumber, prefix, previousLine)
+		}
+		seen[prefix.String()] = lineNumber
+		rules = append(rules, desiredFirewallRule{CIDR: prefix.String(), Label: strings.TrimSpace(label)})
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "read firewall rules")
+	}
+	return rules, nil
+}
+
+// parseCIDR parses a CIDR or a single IP address, and normalizes it so that
+// rules written differently can be compared, e.g. 203.0.113.7/24 and
+// 203.0.113.0/24
+func parseCIDR(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix.Masked(), nil
+}
+
+func addFirewallRule(rules []desiredFirewallRule, rule desiredFirewallRule) []desiredFirewallRule {
+	for _, r := range rules {
+		if r.CIDR == rule.CIDR {
+			return rules
+		}
+	}
+	return append(rules, rule)
+}
+
+// planFirewallRules computes the custom range rules to add and to remove.
+// The labels of the existing rules are not updated as it would require to
+// remove and add the rule again.
+func planFirewallRules(existing []scalingo.FirewallRule, desired []desiredFirewallRule) firewallRulesPlan {
+	var plan firewallRulesPlan
+	existingCIDRs := map[string]bool{}
+	for _, rule := range existing {
+		if rule.Type != scalingo.FirewallRuleTypeCustomRange {
+			continue
+		}
+		cidr := rule.CIDR
+		prefix, err := parseCIDR(rule.CIDR)
+		if err == nil {
+			cidr = prefix.String()
+		}
+
+		if existingCIDRs[cidr] || !containsFirewallRule(desired, cidr) {
+			plan.ToRemove = append(plan.ToRemove, rule)
+			continue
+		}
+		existingCIDRs[cidr] = true
+		plan.Unchanged = append(plan.Unchanged, rule)
+	}
+	for _, rule := range desired {
+		if !existingCIDRs[rule.CIDR] {
+			plan.ToAdd = append(plan.ToAdd, rule)
+		}
+	}
+	return plan
+}
+
+func containsFirewallRule(rules []desiredFirewallRule, cidr string) bool {
+	for _, rule := range rules {
+		if rule.CIDR == cidr {
+			return true
+		}
+	}
+	return false
+}
+
+func renderFirewallRulesPlan(plan firewallRulesPlan) {
+	t := tablewriter.NewWriter(os.Stdout)
+	t.Header([]string{"Action", "CIDR", "Label"})
+	for _, rule := range plan.ToAdd {
+		_ = t.Append([]string{"add", rule.CIDR, rule.Label})
+	}
+	for _, rule := range plan.ToRemove {
+		_ = t.Append([]string{"remove", rule.CIDR, rule.Label})
+	}
+	for _, rule := range plan.Unchanged {
+		_ = t.Append([]string{"keep", rule.CIDR, rule.Label})
+	}
+	_ = t.Render()
+}
+
+// fetchMyIP returns the public IP of the caller, as returned by an endpoint
+// answering the IP address in plain text
+func fetchMyIP(ctx context.Context, endpoint string) (netip.Prefix, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return netip.Prefix{}, errors.Wrapf(ctx, err, "create request to %s", endpoint)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return netip.Prefix{}, errors.Wrapf(ctx, err, "get public IP from %s", endpoint)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return netip.Prefix{}, errors.Newf(ctx, "get public IP from %s: unexpected status %s", endpoint, res.Status)
+	}
+
+	body, err := stdio.ReadAll(stdio.LimitReader(res.Body, 256))
+	if err != nil {
+		return netip.Prefix{}, errors.Wrapf(ctx, err, "read public IP from %s", endpoint)
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(string(body)))
+	if err != nil {
+		return netip.Prefix{}, errors.Newf(ctx, "invalid public IP returned by %s: %q", endpoint, strings.TrimSpace(string(body)))
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
@@ -0,0 +1,70 @@
+package dbng
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestParseFirewallRules(t *testing.T) {
+	tests := map[string]struct {
+		content       string
+		expectedRules []desiredFirewallRule
+		expectedError string
+	}{
+		"CIDRs with labels and comments": {
+			content: "# Office\n203.0.113.7/24 Office network\n\n198.51.100.12 CI runner\n2001:db8::/32\n",
+			expectedRules: []desiredFirewallRule{
+				{CIDR: "203.0.113.0/24", Label: "Office network"},
+				{CIDR: "198.51.100.12/32", Label: "CI runner"},
+				{CIDR: "2001:db8::/32"},
+			},
+		},
+		"invalid CIDR": {
+			content:       "203.0.113.0/24 Office\n203.0.113.300/32 Broken\n",
+			expectedError: "line 2: invalid CIDR '203.0.113.300/32'",
+		},
+		"duplicated CIDR": {
+			content:       "203.0.113.0/24 Office\n203.0.113.1/24 Office again\n",
+			expectedError: "line 2: 203.0.113.0/24 is already allowed on line 1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rules, err := parseFirewallRules(context.Background(), strings.NewReader(test.content))
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRules, rules)
+		})
+	}
+}
+
+func TestPlanFirewallRules(t *testing.T) {
+	existing := []scalingo.FirewallRule{
+		{ID: "managed", Type: scalingo.FirewallRuleTypeManagedRange, RangeID: "mr-scalingo-osc-fr1"},
+		{ID: "office", Type: scalingo.FirewallRuleTypeCustomRange, CIDR: "203.0.113.0/24", Label: "Office"},
+		{ID: "old-ci", Type: scalingo.FirewallRuleTypeCustomRange, CIDR: "198.51.100.12/32", Label: "Old CI"},
+	}
+	desired := []desiredFirewallRule{
+		{CIDR: "203.0.113.0/24", Label: "Office network"},
+		{CIDR: "192.0.2.1/32", Label: "New CI"},
+	}
+
+	plan := planFirewallRules(existing, desired)
+
+	assert.Equal(t, []desiredFirewallRule{{CIDR: "192.0.2.1/32", Label: "New CI"}}, plan.ToAdd)
+	require.Len(t, plan.ToRemove, 1)
+	assert.Equal(t, "old-ci", plan.ToRemove[0].ID)
+	require.Len(t, plan.Unchanged, 1)
+	assert.Equal(t, "office", plan.Unchanged[0].ID)
+}
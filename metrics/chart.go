@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+const lineChartHeight = 8
+
+// resample averages the points in width buckets of the same duration
+// between from and to. The buckets without point are NaN.
+func resample(points []Point, from, to time.Time, width int) []float64 {
+	sums := make([]float64, width)
+	counts := make([]int, width)
+	duration := to.Sub(from)
+	for _, point := range points {
+		bucket := 0
+		if duration > 0 {
+			bucket = int(float64(point.Time.Sub(from)) / float64(duration) * float64(width))
+		}
+		bucket = min(max(bucket, 0), width-1)
+		sums[bucket] += point.Value
+		counts[bucket]++
+	}
+
+	values := make([]float64, width)
+	for i := range values {
+		if counts[i] == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = sums[i] / float64(counts[i])
+	}
+	return values
+}
+
+// level returns the level of the value between 0 and levels-1
+func level(value, minValue, maxValue float64, levels int) int {
+	if maxValue == minValue {
+		return 0
+	}
+	return int(math.Round((value - minValue) / (maxValue - minValue) * float64(levels-1)))
+}
+
+// sparkline renders the values on a single line, the missing values are
+// blank
+func sparkline(values []float64, minValue, maxValue float64) string {
+	var b strings.Builder
+	for _, value := range values {
+		if math.IsNaN(value) {
+			b.WriteRune(' ')
+			continue
+		}
+		b.WriteRune(sparklineLevels[level(value, minValue, maxValue, len(sparklineLevels))])
+	}
+	return b.String()
+}
+
+// lineChart renders the values on height lines, prefixed by the y axis
+func lineChart(values []float64, minValue, maxValue float64, height int, formatValue func(float64) string) []string {
+	top, bottom := formatValue(maxValue), formatValue(minValue)
+	axisWidth := max(len(top), len(bottom))
+
+	lines := make([]string, height)
+	for row := range height {
+		rowLevel := height - 1 - row
+		label := ""
+		switch row {
+		case 0:
+			label = top
+		case height - 1:
+			label = bottom
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%*s ┤", axisWidth, label)
+		for _, value := range values {
+			if !math.IsNaN(value) && level(value, minValue, maxValue, height) == rowLevel {
+				b.WriteRune('•')
+			} else {
+				b.WriteRune(' ')
+			}
+		}
+		lines[row] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// summary is the minimum, average, maximum and last values of a series
+type summary struct {
+	Min, Avg, Max, Last float64
+}
+
+func summarize(points []Point) summary {
+	if len(points) == 0 {
+		return summary{}
+	}
+	s := summary{Min: points[0].Value, Max: points[0].Value, Last: points[len(points)-1].Value}
+	sum := 0.0
+	for _, point := range points {
+		s.Min = min(s.Min, point.Value)
+		s.Max = max(s.Max, point.Value)
+		sum += point.Value
+	}
+	s.Avg = sum / float64(len(points))
+	return s
+}
+
+// valueFormatter returns the function formatting the values of the metric
+func valueFormatter(metric string) func(float64) string {
+	switch metric {
+	case scalingo.MetricMemory, scalingo.MetricSwap:
+		return func(v float64) string { return humanize.IBytes(uint64(max(v, 0))) }
+	case scalingo.MetricRouterP95ResponseTime:
+		return func(v float64) string { return fmt.Sprintf("%.0fms", v) }
+	default:
+		return func(v float64) string {
+			if v == math.Trunc(v) {
+				return fmt.Sprintf("%.0f", v)
+			}
+			return fmt.Sprintf("%.2f", v)
+		}
+	}
+}
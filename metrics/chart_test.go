@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResample(t *testing.T) {
+	from := time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC)
+	points := []Point{
+		{Time: from, Value: 1},
+		{Time: from.Add(10 * time.Minute), Value: 3},
+		{Time: from.Add(50 * time.Minute), Value: 8},
+	}
+
+	values := resample(points, from, from.Add(time.Hour), 4)
+
+	require.Len(t, values, 4)
+	assert.InDelta(t, 2, values[0], 0.001)
+	assert.True(t, math.IsNaN(values[1]))
+	assert.True(t, math.IsNaN(values[2]))
+	assert.InDelta(t, 8, values[3], 0.001)
+}
+
+func TestSparkline(t *testing.T) {
+	tests := map[string]struct {
+		values   []float64
+		expected string
+	}{
+		"increasing values": {
+			values:   []float64{0, 1, 2, 3, 4, 5, 6, 7},
+			expected: "▁▂▃▄▅▆▇█",
+		},
+		"missing values": {
+			values:   []float64{0, math.NaN(), 7},
+			expected: "▁ █",
+		},
+		"constant values": {
+			values:   []float64{5, 5},
+			expected: "▁▁",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			minValue, maxValue := math.Inf(1), math.Inf(-1)
+			for _, v := range test.values {
+				if !math.IsNaN(v) {
+					minValue, maxValue = min(minValue, v), max(maxValue, v)
+				}
+			}
+			assert.Equal(t, test.expected, sparkline(test.values, minValue, maxValue))
+		})
+	}
+}
+
+func TestRenderCharts_Sparkline(t *testing.T) {
+	from := time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC)
+	series := []Series{
+		{Name: "web", Points: []Point{{Time: from, Value: 10}, {Time: from.Add(59 * time.Minute), Value: 20}}},
+		{Name: "worker"},
+	}
+
+	var buffer bytes.Buffer
+	renderCharts(&buffer, series, "rpm_per_container", ChartSparkline, from, from.Add(time.Hour), 60)
+
+	lines := bytes.Split(bytes.TrimSpace(buffer.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Equal(t, 60, len([]rune(string(lines[0]))))
+	assert.Contains(t, string(lines[0]), "min 10  avg 15  max 20  last 20")
+	assert.Equal(t, "worker  no data", string(lines[1]))
+}
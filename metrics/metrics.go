@@ -0,0 +1,227 @@
+package metrics
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	stdio "io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/cli/io"
+	"github.com/Scalingo/cli/term"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	AggregateType  = "type"
+	AggregateIndex = "index"
+
+	ChartSparkline = "sparkline"
+	ChartLine      = "line"
+
+	FormatChart = "chart"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+
+	defaultCols = 80
+)
+
+type ShowOpts struct {
+	Metric string
+	Since  time.Duration
+	// ContainerType restricts the container metrics to a container type
+	ContainerType string
+	// Aggregate is the granularity of the container metrics series: one per
+	// container type or one per container
+	Aggregate string
+	Chart     string
+	Format    string
+}
+
+// Series is the time series of a metric for a container type, a container
+// or the router
+type Series struct {
+	Name           string  `json:"name"`
+	ContainerType  string  `json:"container_type,omitempty"`
+	ContainerIndex int     `json:"container_index,omitempty"`
+	Points         []Point `json:"points"`
+}
+
+// Show fetches the time series of a metric and renders them as charts, or
+// exports them as JSON or CSV
+func Show(ctx context.Context, app string, opts ShowOpts) error {
+	if !IsValidMetric(opts.Metric) {
+		return errors.Newf(ctx, "unknown metric '%s', available metrics: %s", opts.Metric, strings.Join(append(ContainerMetrics, RouterMetrics...), ", "))
+	}
+	if opts.Aggregate == AggregateIndex && !IsContainerMetric(opts.Metric) {
+		return errors.Newf(ctx, "the %s metric can't be aggregated per container", opts.Metric)
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	queries, err := seriesQueries(ctx, c, app, opts)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	series := make([]Series, 0, len(queries))
+	for _, s := range queries {
+		points, err := Fetch(ctx, c, app, Query{
+			Metric:         opts.Metric,
+			Since:          opts.Since,
+			ContainerType:  s.ContainerType,
+			ContainerIndex: s.ContainerIndex,
+		}, now)
+		if err != nil {
+			return err
+		}
+		s.Points = points
+		series = append(series, s)
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		return writeJSON(ctx, os.Stdout, series)
+	case FormatCSV:
+		return writeCSV(ctx, os.Stdout, series)
+	}
+
+	cols, err := term.Cols(ctx)
+	if err != nil {
+		cols = defaultCols
+	}
+	io.Statusf("%s of %s since %s\n", opts.Metric, app, now.Add(-opts.Since).Format("2006-01-02 15:04"))
+	renderCharts(os.Stdout, series, opts.Metric, opts.Chart, now.Add(-opts.Since), now, cols)
+	return nil
+}
+
+// ParseSince parses the duration of the time series, e.g. 30m, 6h or 7d
+func ParseSince(ctx context.Context, s string) (time.Duration, error) {
+	since, err := time.ParseDuration(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		since = time.Duration(n) * 24 * time.Hour
+	}
+	if err != nil || since <= 0 {
+		return 0, errors.Newf(ctx, "invalid duration '%s', expected e.g. 30m, 6h or 7d", s)
+	}
+	return since, nil
+}
+
+// seriesQueries returns the series to fetch, without their points
+func seriesQueries(ctx context.Context, c *scalingo.Client, app string, opts ShowOpts) ([]Series, error) {
+	if !IsContainerMetric(opts.Metric) {
+		return []Series{{Name: "router"}}, nil
+	}
+
+	containerTypes, err := c.AppsContainerTypes(ctx, app)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "list container types of %s", app)
+	}
+	var series []Series
+	for _, containerType := range containerTypes {
+		if opts.ContainerType != "" && containerType.Name != opts.ContainerType {
+			continue
+		}
+		if containerType.Amount == 0 {
+			continue
+		}
+		if opts.Aggregate != AggregateIndex {
+			series = append(series, Series{Name: containerType.Name, ContainerType: containerType.Name})
+			continue
+		}
+		for index := 1; index <= containerType.Amount; index++ {
+			series = append(series, Series{
+				Name:           fmt.Sprintf("%s-%d", containerType.Name, index),
+				ContainerType:  containerType.Name,
+				ContainerIndex: index,
+			})
+		}
+	}
+	if len(series) == 0 {
+		if opts.ContainerType != "" {
+			return nil, errors.Newf(ctx, "no running container of type %s", opts.ContainerType)
+		}
+		return nil, errors.Newf(ctx, "no running container for %s", app)
+	}
+	return series, nil
+}
+
+// renderCharts renders a sparkline per series sized to the width of the
+// terminal, followed by the summary of the series, or a line chart per series
+func renderCharts(w stdio.Writer, series []Series, metric, chart string, from, to time.Time, cols int) {
+	formatValue := valueFormatter(metric)
+	summaries := make([]string, len(series))
+	nameWidth, summaryWidth := 0, 0
+	for i, s := range series {
+		if len(s.Points) > 0 {
+			sum := summarize(s.Points)
+			summaries[i] = fmt.Sprintf("min %s  avg %s  max %s  last %s", formatValue(sum.Min), formatValue(sum.Avg), formatValue(sum.Max), formatValue(sum.Last))
+		}
+		nameWidth = max(nameWidth, len(s.Name))
+		summaryWidth = max(summaryWidth, len(summaries[i]))
+	}
+
+	for i, s := range series {
+		if len(s.Points) == 0 {
+			fmt.Fprintf(w, "%-*s  no data\n", nameWidth, s.Name)
+			continue
+		}
+		sum := summarize(s.Points)
+		if chart == ChartLine {
+			fmt.Fprintf(w, "%s  %s\n", s.Name, summaries[i])
+			axisWidth := max(len(formatValue(sum.Min)), len(formatValue(sum.Max))) + 2
+			width := max(cols-axisWidth, 10)
+			for _, line := range lineChart(resample(s.Points, from, to, width), sum.Min, sum.Max, lineChartHeight, formatValue) {
+				fmt.Fprintln(w, line)
+			}
+			fmt.Fprintf(w, "%*s└%s\n", axisWidth-1, "", strings.Repeat("─", width))
+			start, end := from.Format("15:04"), to.Format("15:04")
+			fmt.Fprintf(w, "%*s%s%*s\n\n", axisWidth, "", start, max(width-len(start), len(end)), end)
+			continue
+		}
+
+		width := max(cols-nameWidth-summaryWidth-4, 10)
+		fmt.Fprintf(w, "%-*s  %s  %s\n", nameWidth, s.Name, sparkline(resample(s.Points, from, to, width), sum.Min, sum.Max), summaries[i])
+	}
+}
+
+func writeJSON(ctx context.Context, w stdio.Writer, series []Series) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(series)
+	if err != nil {
+		return errors.Wrap(ctx, err, "encode metrics to JSON")
+	}
+	return nil
+}
+
+func writeCSV(ctx context.Context, w stdio.Writer, series []Series) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"series", "container_type", "container_index", "time", "value"})
+	for _, s := range series {
+		index := ""
+		if s.ContainerIndex > 0 {
+			index = strconv.Itoa(s.ContainerIndex)
+		}
+		for _, point := range s.Points {
+			_ = writer.Write([]string{s.Name, s.ContainerType, index, point.Time.Format(time.RFC3339), strconv.FormatFloat(point.Value, 'f', -1, 64)})
+		}
+	}
+	writer.Flush()
+	err := writer.Error()
+	if err != nil {
+		return errors.Wrap(ctx, err, "write metrics as CSV")
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/Scalingo/go-scalingo/v11"
+	httpclient "github.com/Scalingo/go-scalingo/v11/http"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// ContainerMetrics are the metrics of the containers, RouterMetrics are the
+// metrics of the requests routed to the web containers
+var (
+	ContainerMetrics = []string{scalingo.MetricCPU, scalingo.MetricMemory, scalingo.MetricSwap}
+	RouterMetrics    = []string{
+		scalingo.MetricRouterAll,
+		scalingo.MetricRouter5XX,
+		scalingo.MetricRouterServersAmount,
+		scalingo.MetricRouterRPMPerContainer,
+		scalingo.MetricRouterP95ResponseTime,
+	}
+)
+
+type Point struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Query is a query of the time series of a metric. The container type and
+// index are only used by the container metrics.
+type Query struct {
+	Metric         string
+	Since          time.Duration
+	ContainerType  string
+	ContainerIndex int
+}
+
+func IsContainerMetric(metric string) bool {
+	return slices.Contains(ContainerMetrics, metric)
+}
+
+func IsValidMetric(metric string) bool {
+	return IsContainerMetric(metric) || slices.Contains(RouterMetrics, metric)
+}
+
+// Fetch returns the points of the time series of a metric since the
+// duration of the query, ordered by time
+func Fetch(ctx context.Context, c *scalingo.Client, app string, query Query, now time.Time) ([]Point, error) {
+	params := map[string]string{
+		// The API only accepts a number of hours, the points are filtered below
+		"since": strconv.Itoa(int(math.Ceil(query.Since.Hours()))),
+	}
+	if query.ContainerType != "" && IsContainerMetric(query.Metric) {
+		params["container_type"] = query.ContainerType
+	}
+	if query.ContainerIndex > 0 && IsContainerMetric(query.Metric) {
+		params["container_index"] = strconv.Itoa(query.ContainerIndex)
+	}
+
+	var points []Point
+	err := c.ScalingoAPI().DoRequest(ctx, &httpclient.APIRequest{
+		Method:   http.MethodGet,
+		Endpoint: "/apps/" + app + "/stats/" + query.Metric,
+		Params:   params,
+		Expected: httpclient.Statuses{http.StatusOK},
+	}, &points)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "fetch metric %s of %s", query.Metric, app)
+	}
+
+	from := now.Add(-query.Since)
+	points = slices.DeleteFunc(points, func(point Point) bool {
+		return point.Time.Before(from)
+	})
+	slices.SortFunc(points, func(a, b Point) int {
+		return a.Time.Compare(b.Time)
+	})
+	return points, nil
+}
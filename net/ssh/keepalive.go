@@ -0,0 +1,46 @@
+package ssh
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const keepAliveRequest = "keepalive@openssh.com"
+
+// KeepAlive sends a keepalive request to the SSH server every interval. It
+// returns when the context is canceled or as soon as the server fails to
+// answer within timeout, in which case the connection should be considered
+// dead.
+func KeepAlive(ctx context.Context, client *ssh.Client, interval, timeout time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		replied := make(chan error, 1)
+		go func() {
+			_, _, err := client.SendRequest(keepAliveRequest, true, nil)
+			replied <- err
+		}()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-replied:
+			if err != nil {
+				return errors.Wrap(ctx, err, "send SSH keepalive")
+			}
+		case <-time.After(timeout):
+			return errors.Newf(ctx, "no answer to SSH keepalive after %v", timeout)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/Scalingo/cli/utils"
+	"github.com/Scalingo/go-scalingo/v11/debug"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+var reconnectMaxWait = time.Minute
+
+type SharedClientOpts struct {
+	Host     string
+	Identity string
+	// KeepAliveInterval is the interval between two keepalive requests. A zero
+	// value disables keepalives.
+	KeepAliveInterval time.Duration
+	// Reconnect makes the client transparently rebuild the SSH connection when
+	// it drops. Otherwise the failure is sent on the Failures channel.
+	Reconnect bool
+}
+
+// SharedClient is an SSH connection shared by all the connections forwarded
+// through the SSH gateway. It is monitored with keepalives and its state is
+// reported on stderr.
+type SharedClient struct {
+	opts     SharedClientOpts
+	failures chan error
+
+	mutex  sync.Mutex
+	client *ssh.Client
+}
+
+func NewSharedClient(opts SharedClientOpts) *SharedClient {
+	return &SharedClient{
+		opts:     opts,
+		failures: make(chan error, 1),
+	}
+}
+
+// Connect establishes the SSH connection if it is not already established.
+func (c *SharedClient) Connect(ctx context.Context) error {
+	_, err := c.get(ctx)
+	return err
+}
+
+// Failures receives the error which made the SSH connection drop when
+// reconnection is disabled.
+func (c *SharedClient) Failures() <-chan error {
+	return c.failures
+}
+
+// Dial opens a TCP connection to addr through the SSH connection. If the SSH
+// connection is broken and reconnection is enabled, it is rebuilt until it
+// succeeds. The rejection of the connection by the SSH gateway, e.g. for an
+// unreachable host, is returned right away and keeps the SSH connection.
+func (c *SharedClient) Dial(ctx context.Context, addr string) (net.Conn, error) {
+	for {
+		client, err := c.get(ctx)
+		if err == nil {
+			var conn net.Conn
+			conn, err = client.Dial("tcp", addr)
+			if err == nil {
+				return conn, nil
+			}
+			var openChannelErr *ssh.OpenChannelError
+			if errors.As(err, &openChannelErr) {
+				return nil, err
+			}
+			c.reset(client)
+		}
+		if !c.opts.Reconnect {
+			return nil, err
+		}
+		debug.Println("Fail to use SSH connection:", err)
+		printStatus("fail to reconnect, waiting 10 seconds...")
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+func (c *SharedClient) Close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+func (c *SharedClient) get(ctx context.Context) (*ssh.Client, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	// Do not reuse key since the connection to the SSH agent might be broken
+	client, _, err := Connect(ctx, ConnectOpts{
+		Host:     c.opts.Host,
+		Identity: c.opts.Identity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.client = client
+	printStatus("connected to %s", c.opts.Host)
+	if c.opts.KeepAliveInterval > 0 {
+		go c.monitor(ctx, client)
+	}
+	return client, nil
+}
+
+// monitor sends keepalives on the given client until it fails, then either
+// reconnects or reports the failure.
+func (c *SharedClient) monitor(ctx context.Context, client *ssh.Client) {
+	err := KeepAlive(ctx, client, c.opts.KeepAliveInterval, c.opts.KeepAliveInterval)
+	if err == nil {
+		// The context has been canceled
+		return
+	}
+	debug.Println("SSH keepalive failed:", err)
+	printStatus("connection to %s lost (%v)", c.opts.Host, err)
+	c.reset(client)
+
+	if !c.opts.Reconnect {
+		select {
+		case c.failures <- err:
+		default:
+		}
+		return
+	}
+
+	wait := time.Second
+	for {
+		printStatus("reconnecting to %s...", c.opts.Host)
+		_, err := c.get(ctx)
+		if err == nil {
+			return
+		}
+		debug.Println("Fail to reconnect to SSH server:", err)
+		printStatus("fail to reconnect, waiting %v...", wait)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait = min(2*wait, reconnectMaxWait)
+	}
+}
+
+// reset drops the given client if it is still the current one, so that the
+// next call to get establishes a new SSH connection.
+func (c *SharedClient) reset(broken *ssh.Client) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.client == broken {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// printStatus writes a status line about the SSH connection on stderr so that
+// it does not mix with the output of the command.
+func printStatus(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "[%s] SSH: %s\n", time.Now().Format(utils.TimeFormat), fmt.Sprintf(format, args...))
+}
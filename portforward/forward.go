@@ -0,0 +1,212 @@
+// Package portforward forwards local connections to any host reachable from
+// the SSH gateway of a region, either to a fixed remote address or through a
+// SOCKS5 proxy.
+package portforward
+
+import (
+	"context"
+	"fmt"
+	stdio "io"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/Scalingo/cli/config"
+	netssh "github.com/Scalingo/cli/net/ssh"
+	"github.com/Scalingo/cli/signals"
+	"github.com/Scalingo/go-scalingo/v11/debug"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const defaultBind = "127.0.0.1"
+
+// Forwarding is a local address forwarded to a remote address.
+type Forwarding struct {
+	Local  string
+	Remote string
+}
+
+type Opts struct {
+	Identity          string
+	Reconnect         bool
+	KeepAliveInterval time.Duration
+}
+
+// ParseForwarding parses a forwarding given as '[bind:]port:host:hostport',
+// the same format as the '-L' option of OpenSSH. The IPv6 addresses are
+// enclosed in square brackets, e.g. '[::1]:8080:[fd00::1]:80'.
+func ParseForwarding(ctx context.Context, value string) (Forwarding, error) {
+	parts, ok := splitForwarding(value)
+	if !ok {
+		return Forwarding{}, errors.Newf(ctx, "invalid forwarding '%s', unclosed square bracket", value)
+	}
+	if len(parts) == 3 {
+		parts = append([]string{defaultBind}, parts...)
+	}
+	if len(parts) != 4 || parts[0] == "" || parts[2] == "" {
+		return Forwarding{}, errors.Newf(ctx, "invalid forwarding '%s', format is '[bind:]port:host:hostport'", value)
+	}
+
+	for _, port := range []string{parts[1], parts[3]} {
+		p, err := strconv.Atoi(port)
+		if err != nil || p <= 0 || p > 65535 {
+			return Forwarding{}, errors.Newf(ctx, "invalid port '%s' in '%s'", port, value)
+		}
+	}
+
+	return Forwarding{
+		Local:  net.JoinHostPort(parts[0], parts[1]),
+		Remote: net.JoinHostPort(parts[2], parts[3]),
+	}, nil
+}
+
+// splitForwarding splits the forwarding on the colons outside of square
+// brackets. The brackets enclosing a part are removed.
+func splitForwarding(value string) ([]string, bool) {
+	var parts []string
+	var part strings.Builder
+	inBrackets := false
+	for _, r := range value {
+		switch {
+		case r == '[' && !inBrackets && part.Len() == 0:
+			inBrackets = true
+		case r == ']' && inBrackets:
+			inBrackets = false
+		case r == ':' && !inBrackets:
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	if inBrackets {
+		return nil, false
+	}
+	return append(parts, part.String()), true
+}
+
+// Forward listens on the local address of each forwarding and forwards every
+// connection to the remote address through the SSH gateway of the current
+// region. It returns when interrupted.
+func Forward(ctx context.Context, forwardings []Forwarding, opts Opts) error {
+	return serve(ctx, opts, func(ctx context.Context, sshClient *netssh.SharedClient) ([]net.Listener, error) {
+		listeners := make([]net.Listener, 0, len(forwardings))
+		t := tablewriter.NewWriter(os.Stdout)
+		t.Header([]string{"Local Address", "Remote Address"})
+		for _, forwarding := range forwardings {
+			listener, err := net.Listen("tcp", forwarding.Local)
+			if err != nil {
+				closeListeners(listeners)
+				return nil, errors.Wrapf(ctx, err, "listen on %s", forwarding.Local)
+			}
+			listeners = append(listeners, listener)
+			_ = t.Append([]string{listener.Addr().String(), forwarding.Remote})
+
+			go acceptConnections(listener, func(conn net.Conn) {
+				forwardConn(ctx, sshClient, conn, forwarding.Remote)
+			})
+		}
+		_ = t.Render()
+		return listeners, nil
+	})
+}
+
+// serve connects to the SSH gateway, lets listen start the listeners and
+// waits for an interruption or a failure of the SSH connection.
+func serve(ctx context.Context, opts Opts, listen func(context.Context, *netssh.SharedClient) ([]net.Listener, error)) error {
+	region, err := config.GetRegion(ctx, config.C, config.C.ScalingoRegion, config.GetRegionOpts{})
+	if err != nil {
+		return errors.Wrapf(ctx, err, "fail to retrieve region information")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sshClient := netssh.NewSharedClient(netssh.SharedClientOpts{
+		Host:              region.SSH,
+		Identity:          opts.Identity,
+		KeepAliveInterval: opts.KeepAliveInterval,
+		Reconnect:         opts.Reconnect,
+	})
+	err = sshClient.Connect(ctx)
+	if err != nil {
+		if errors.Is(err, netssh.ErrNoAuthSucceed) {
+			return errors.Wrapf(ctx, err, "please use the flag '-i /path/to/private/key' to specify your private key")
+		}
+		return errors.Wrapf(ctx, err, "fail to connect to SSH server")
+	}
+	defer sshClient.Close()
+
+	signals.CatchQuitSignals = false
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	listeners, err := listen(ctx, sshClient)
+	if err != nil {
+		return err
+	}
+	defer closeListeners(listeners)
+
+	select {
+	case err := <-sshClient.Failures():
+		return errors.Wrap(ctx, err, "SSH connection lost")
+	case <-interrupt:
+		return nil
+	}
+}
+
+func acceptConnections(listener net.Listener, handle func(net.Conn)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			debug.Println("Stop accepting connections on", listener.Addr(), err)
+			return
+		}
+		go handle(conn)
+	}
+}
+
+func forwardConn(ctx context.Context, sshClient *netssh.SharedClient, local net.Conn, remoteAddr string) {
+	defer local.Close()
+
+	remote, err := sshClient.Dial(ctx, remoteAddr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Fail to connect to %s: %v\n", remoteAddr, err)
+		return
+	}
+	defer remote.Close()
+
+	debug.Println("Forwarding", local.RemoteAddr(), "to", remoteAddr)
+	pipe(local, remote)
+}
+
+// pipe copies data in both directions until one side closes its connection.
+func pipe(local, remote net.Conn) {
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, _ = stdio.Copy(local, remote)
+		local.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		_, _ = stdio.Copy(remote, local)
+		remote.Close()
+	}()
+	wg.Wait()
+}
+
+func closeListeners(listeners []net.Listener) {
+	for _, listener := range listeners {
+		listener.Close()
+	}
+}
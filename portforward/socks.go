@@ -0,0 +1,224 @@
+package portforward
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	stdio "io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	netssh "github.com/Scalingo/cli/net/ssh"
+	"github.com/Scalingo/go-scalingo/v11/debug"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// SOCKS5 protocol constants, see RFC 1928
+const (
+	socksVersion = 0x05
+
+	socksMethodNoAuth       = 0x00
+	socksMethodNoAcceptable = 0xff
+
+	socksCommandConnect = 0x01
+
+	socksAddrIPv4   = 0x01
+	socksAddrDomain = 0x03
+	socksAddrIPv6   = 0x04
+
+	socksReplySucceeded          = 0x00
+	socksReplyGeneralFailure     = 0x01
+	socksReplyCommandUnsupported = 0x07
+	socksReplyAddrUnsupported    = 0x08
+)
+
+type socksError struct {
+	reply byte
+	msg   string
+}
+
+func (e socksError) Error() string {
+	return e.msg
+}
+
+type SOCKSProxyOpts struct {
+	Bind string
+	Port int
+	// AllowRemote allows to bind a non-loopback address. The proxy has no
+	// authentication, anyone reaching the address can use it.
+	AllowRemote bool
+}
+
+// SOCKSProxy starts a SOCKS5 proxy listening on bind:port. Every connection
+// requested to the proxy is opened through the SSH gateway of the current
+// region. It returns when interrupted.
+func SOCKSProxy(ctx context.Context, proxyOpts SOCKSProxyOpts, opts Opts) error {
+	bind := proxyOpts.Bind
+	if bind == "" {
+		bind = defaultBind
+	}
+	if !proxyOpts.AllowRemote && !isLoopback(bind) {
+		return errors.Newf(ctx, "refusing to bind the SOCKS proxy on the non-loopback address %s: the proxy has no authentication, anyone reaching it could use it, use --allow-remote to bind it anyway", bind)
+	}
+	addr := net.JoinHostPort(bind, strconv.Itoa(proxyOpts.Port))
+
+	return serve(ctx, opts, func(ctx context.Context, sshClient *netssh.SharedClient) ([]net.Listener, error) {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "listen on %s", addr)
+		}
+		fmt.Fprintln(os.Stderr, "SOCKS5 proxy listening on:")
+		fmt.Println(listener.Addr())
+
+		go acceptConnections(listener, func(conn net.Conn) {
+			defer conn.Close()
+			err := serveSOCKS(ctx, conn, func(addr string) (net.Conn, error) {
+				return sshClient.Dial(ctx, addr)
+			})
+			if err != nil {
+				debug.Println("SOCKS connection from", conn.RemoteAddr(), "failed:", err)
+			}
+		})
+		return []net.Listener{listener}, nil
+	})
+}
+
+// isLoopback returns whether the bind address only accepts local
+// connections
+func isLoopback(bind string) bool {
+	if bind == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(bind, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveSOCKS handles a single SOCKS5 client connection: it negotiates the
+// authentication, reads the CONNECT request, opens the connection with dial
+// and pipes the data.
+func serveSOCKS(ctx context.Context, conn net.Conn, dial func(addr string) (net.Conn, error)) error {
+	reader := bufio.NewReader(conn)
+
+	err := socksNegotiate(ctx, reader, conn)
+	if err != nil {
+		return err
+	}
+
+	addr, err := socksReadRequest(ctx, reader)
+	if err != nil {
+		var sErr socksError
+		if errors.As(err, &sErr) {
+			_ = socksWriteReply(conn, sErr.reply)
+		}
+		return err
+	}
+
+	remote, err := dial(addr)
+	if err != nil {
+		_ = socksWriteReply(conn, socksReplyGeneralFailure)
+		return errors.Wrapf(ctx, err, "dial %s", addr)
+	}
+	defer remote.Close()
+
+	err = socksWriteReply(conn, socksReplySucceeded)
+	if err != nil {
+		return err
+	}
+
+	debug.Println("SOCKS proxying", conn.RemoteAddr(), "to", addr)
+	// Data already buffered by the reader must be sent first
+	if reader.Buffered() > 0 {
+		buffered, _ := reader.Peek(reader.Buffered())
+		_, err := remote.Write(buffered)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "write to %s", addr)
+		}
+	}
+	pipe(conn, remote)
+	return nil
+}
+
+func socksNegotiate(ctx context.Context, reader *bufio.Reader, w stdio.Writer) error {
+	header := make([]byte, 2)
+	_, err := stdio.ReadFull(reader, header)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read greeting")
+	}
+	if header[0] != socksVersion {
+		return errors.Newf(ctx, "unsupported SOCKS version %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	_, err = stdio.ReadFull(reader, methods)
+	if err != nil {
+		return errors.Wrap(ctx, err, "read authentication methods")
+	}
+	for _, method := range methods {
+		if method == socksMethodNoAuth {
+			_, err = w.Write([]byte{socksVersion, socksMethodNoAuth})
+			return err
+		}
+	}
+	_, _ = w.Write([]byte{socksVersion, socksMethodNoAcceptable})
+	return errors.New(ctx, "no supported authentication method")
+}
+
+func socksReadRequest(ctx context.Context, reader *bufio.Reader) (string, error) {
+	header := make([]byte, 4)
+	_, err := stdio.ReadFull(reader, header)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "read request")
+	}
+	if header[0] != socksVersion {
+		return "", errors.Newf(ctx, "unsupported SOCKS version %d", header[0])
+	}
+	if header[1] != socksCommandConnect {
+		return "", socksError{reply: socksReplyCommandUnsupported, msg: fmt.Sprintf("unsupported command %d", header[1])}
+	}
+
+	var host string
+	switch header[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		size := net.IPv4len
+		if header[3] == socksAddrIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		_, err = stdio.ReadFull(reader, ip)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "read IP address")
+		}
+		host = net.IP(ip).String()
+	case socksAddrDomain:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "read domain length")
+		}
+		domain := make([]byte, length)
+		_, err = stdio.ReadFull(reader, domain)
+		if err != nil {
+			return "", errors.Wrap(ctx, err, "read domain")
+		}
+		host = string(domain)
+	default:
+		return "", socksError{reply: socksReplyAddrUnsupported, msg: fmt.Sprintf("unsupported address type %d", header[3])}
+	}
+
+	port := make([]byte, 2)
+	_, err = stdio.ReadFull(reader, port)
+	if err != nil {
+		return "", errors.Wrap(ctx, err, "read port")
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// socksWriteReply writes a reply with an unspecified bound address, which
+// clients do not use for CONNECT requests.
+func socksWriteReply(w stdio.Writer, reply byte) error {
+	_, err := w.Write([]byte{socksVersion, reply, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
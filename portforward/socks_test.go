@@ -0,0 +1,155 @@
+package portforward
+
+import (
+	"context"
+	stdio "io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseForwarding(t *testing.T) {
+	tests := map[string]struct {
+		value              string
+		expectedForwarding Forwarding
+		expectedError      string
+	}{
+		"without bind address": {
+			value:              "8080:my-host:80",
+			expectedForwarding: Forwarding{Local: "127.0.0.1:8080", Remote: "my-host:80"},
+		},
+		"with bind address": {
+			value:              "0.0.0.0:8080:my-host:80",
+			expectedForwarding: Forwarding{Local: "0.0.0.0:8080", Remote: "my-host:80"},
+		},
+		"with IPv6 addresses": {
+			value:              "[::1]:8080:[fd00::1]:80",
+			expectedForwarding: Forwarding{Local: "[::1]:8080", Remote: "[fd00::1]:80"},
+		},
+		"with IPv6 remote address": {
+			value:              "8080:[fd00::1]:80",
+			expectedForwarding: Forwarding{Local: "127.0.0.1:8080", Remote: "[fd00::1]:80"},
+		},
+		"unclosed square bracket": {
+			value:         "[::1:8080:my-host:80",
+			expectedError: "unclosed square bracket",
+		},
+		"missing remote port": {
+			value:         "8080:my-host",
+			expectedError: "format is '[bind:]port:host:hostport'",
+		},
+		"invalid local port": {
+			value:         "http:my-host:80",
+			expectedError: "invalid port 'http'",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			forwarding, err := ParseForwarding(context.Background(), test.value)
+			if test.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedForwarding, forwarding)
+		})
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]struct {
+		bind     string
+		expected bool
+	}{
+		"IPv4 loopback":      {bind: "127.0.0.1", expected: true},
+		"IPv6 loopback":      {bind: "::1", expected: true},
+		"bracketed IPv6":     {bind: "[::1]", expected: true},
+		"localhost":          {bind: "localhost", expected: true},
+		"all IPv4 addresses": {bind: "0.0.0.0"},
+		"all IPv6 addresses": {bind: "::"},
+		"private address":    {bind: "192.168.1.10"},
+		"host name":          {bind: "my-host"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, isLoopback(test.bind))
+		})
+	}
+}
+
+func TestServeSOCKS(t *testing.T) {
+	tests := map[string]struct {
+		request      []byte
+		expectedAddr string
+		expectedRep  byte
+	}{
+		"domain name": {
+			request:      append(append([]byte{0x05, 0x01, 0x00, 0x03, 7}, []byte("my-host")...), 0x1f, 0x90),
+			expectedAddr: "my-host:8080",
+			expectedRep:  socksReplySucceeded,
+		},
+		"IPv4 address": {
+			request:      []byte{0x05, 0x01, 0x00, 0x01, 10, 0, 0, 1, 0x00, 0x50},
+			expectedAddr: "10.0.0.1:80",
+			expectedRep:  socksReplySucceeded,
+		},
+		"unsupported command": {
+			request:     []byte{0x05, 0x02, 0x00, 0x01, 10, 0, 0, 1, 0x00, 0x50},
+			expectedRep: socksReplyCommandUnsupported,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			client, server := net.Pipe()
+			defer client.Close()
+
+			var dialedAddr string
+			done := make(chan error, 1)
+			go func() {
+				defer server.Close()
+				done <- serveSOCKS(t.Context(), server, func(addr string) (net.Conn, error) {
+					dialedAddr = addr
+					remote, remoteServer := net.Pipe()
+					go func() {
+						_, _ = stdio.Copy(remoteServer, remoteServer)
+					}()
+					return remote, nil
+				})
+			}()
+
+			_, err := client.Write([]byte{0x05, 0x01, socksMethodNoAuth})
+			require.NoError(t, err)
+			methodReply := make([]byte, 2)
+			_, err = stdio.ReadFull(client, methodReply)
+			require.NoError(t, err)
+			assert.Equal(t, []byte{0x05, socksMethodNoAuth}, methodReply)
+
+			_, err = client.Write(test.request)
+			require.NoError(t, err)
+			reply := make([]byte, 10)
+			_, err = stdio.ReadFull(client, reply)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedRep, reply[1])
+
+			if test.expectedRep != socksReplySucceeded {
+				require.Error(t, <-done)
+				return
+			}
+			assert.Equal(t, test.expectedAddr, dialedAddr)
+
+			// The remote end echoes what it receives
+			_, err = client.Write([]byte("ping"))
+			require.NoError(t, err)
+			echo := make([]byte, 4)
+			_, err = stdio.ReadFull(client, echo)
+			require.NoError(t, err)
+			assert.Equal(t, "ping", string(echo))
+		})
+	}
+}
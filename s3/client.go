@@ -0,0 +1,318 @@
+// Package s3 is a minimal client of the S3 API, used to store files on AWS S3
+// or on any S3-compatible object storage. Requests are authenticated with AWS
+// Signature Version 4 and use path-style URLs.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	defaultRegion = "us-east-1"
+	// minPartSize is the size of the parts of multipart uploads. Files smaller
+	// than a part are uploaded with a single request.
+	minPartSize = 64 * 1024 * 1024
+	maxParts    = 10000
+)
+
+// ErrNotFound is returned when the requested object does not exist
+var ErrNotFound = stderrors.New("object not found")
+
+type Config struct {
+	// Endpoint is the URL of the S3 API, e.g. https://s3.fr-par.scw.cloud
+	Endpoint        string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// ConfigFromEnv reads the configuration from the standard AWS environment
+// variables. The endpoint is read from AWS_ENDPOINT_URL_S3 or
+// AWS_ENDPOINT_URL, and defaults to the AWS S3 endpoint of the region.
+func ConfigFromEnv() Config {
+	config := Config{
+		Endpoint:        os.Getenv("AWS_ENDPOINT_URL_S3"),
+		Region:          os.Getenv("AWS_REGION"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	if config.Region == "" {
+		config.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if config.Region == "" {
+		config.Region = defaultRegion
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	return config
+}
+
+type Client struct {
+	config     Config
+	httpClient *http.Client
+}
+
+// Object is an object returned when listing a bucket
+type Object struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+func New(config Config) *Client {
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &Client{config: config, httpClient: http.DefaultClient}
+}
+
+// ListObjects lists all the objects of the bucket whose key starts with
+// prefix.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix string) ([]Object, error) {
+	var objects []Object
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+		var result struct {
+			Contents              []Object `xml:"Contents"`
+			IsTruncated           bool     `xml:"IsTruncated"`
+			NextContinuationToken string   `xml:"NextContinuationToken"`
+		}
+		err := c.do(ctx, http.MethodGet, bucket, "", query, nil, &result)
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "list objects of %s", bucket)
+		}
+		objects = append(objects, result.Contents...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// GetObject returns the content of an object. It returns ErrNotFound if the
+// object does not exist.
+func (c *Client) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+	res, err := c.send(ctx, http.MethodGet, bucket, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrapf(ctx, err, "read object %s", key)
+	}
+	return content, nil
+}
+
+// PutObject creates or replaces an object with content
+func (c *Client) PutObject(ctx context.Context, bucket, key string, content []byte) error {
+	err := c.do(ctx, http.MethodPut, bucket, key, nil, bytes.NewReader(content), nil)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "put object %s", key)
+	}
+	return nil
+}
+
+func (c *Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	err := c.do(ctx, http.MethodDelete, bucket, key, nil, nil, nil)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "delete object %s", key)
+	}
+	return nil
+}
+
+// UploadFile uploads a local file to the object key. Large files are
+// uploaded with a multipart upload.
+func (c *Client) UploadFile(ctx context.Context, bucket, key, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(ctx, err, "open file")
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return errors.Wrap(ctx, err, "stat file")
+	}
+
+	partSize := partSizeFor(stat.Size())
+	if stat.Size() <= partSize {
+		err := c.do(ctx, http.MethodPut, bucket, key, nil, io.NewSectionReader(f, 0, stat.Size()), nil)
+		if err != nil {
+			return errors.Wrapf(ctx, err, "upload %s", key)
+		}
+		return nil
+	}
+	return c.multipartUpload(ctx, bucket, key, f, stat.Size(), partSize)
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+func (c *Client) multipartUpload(ctx context.Context, bucket, key string, f *os.File, size, partSize int64) error {
+	var upload struct {
+		UploadID string `xml:"UploadId"`
+	}
+	err := c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploads": {""}}, nil, &upload)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "create multipart upload of %s", key)
+	}
+
+	parts, err := c.uploadParts(ctx, bucket, key, upload.UploadID, f, size, partSize)
+	if err != nil {
+		// The uploaded parts are deleted
+		_ = c.do(ctx, http.MethodDelete, bucket, key, url.Values{"uploadId": {upload.UploadID}}, nil, nil)
+		return errors.Wrapf(ctx, err, "upload %s", key)
+	}
+
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return errors.Wrap(ctx, err, "encode completed parts")
+	}
+	err = c.do(ctx, http.MethodPost, bucket, key, url.Values{"uploadId": {upload.UploadID}}, bytes.NewReader(body), nil)
+	if err != nil {
+		return errors.Wrapf(ctx, err, "complete multipart upload of %s", key)
+	}
+	return nil
+}
+
+func (c *Client) uploadParts(ctx context.Context, bucket, key, uploadID string, f *os.File, size, partSize int64) ([]completedPart, error) {
+	var parts []completedPart
+	for offset, partNumber := int64(0), 1; offset < size; offset, partNumber = offset+partSize, partNumber+1 {
+		query := url.Values{"partNumber": {strconv.Itoa(partNumber)}, "uploadId": {uploadID}}
+		res, err := c.send(ctx, http.MethodPut, bucket, key, query, io.NewSectionReader(f, offset, min(partSize, size-offset)))
+		if err != nil {
+			return nil, errors.Wrapf(ctx, err, "upload part %d", partNumber)
+		}
+		res.Body.Close()
+		parts = append(parts, completedPart{PartNumber: partNumber, ETag: res.Header.Get("ETag")})
+	}
+	return parts, nil
+}
+
+func partSizeFor(size int64) int64 {
+	partSize := int64(minPartSize)
+	for size > partSize*maxParts {
+		partSize *= 2
+	}
+	return partSize
+}
+
+// do sends a request and decodes the XML response in result if it is not nil
+func (c *Client) do(ctx context.Context, method, bucket, key string, query url.Values, body io.ReadSeeker, result any) error {
+	res, err := c.send(ctx, method, bucket, key, query, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if result == nil {
+		return nil
+	}
+	err = xml.NewDecoder(res.Body).Decode(result)
+	if err != nil {
+		return errors.Wrap(ctx, err, "decode response")
+	}
+	return nil
+}
+
+// send sends a signed request. It returns an error if the response status is
+// not successful.
+func (c *Client) send(ctx context.Context, method, bucket, key string, query url.Values, body io.ReadSeeker) (*http.Response, error) {
+	payloadHash, size, err := hashPayload(body)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "hash request body")
+	}
+
+	u, err := url.Parse(c.config.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "invalid endpoint")
+	}
+	u.Path += "/" + bucket
+	if key != "" {
+		u.Path += "/" + key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = body
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), reqBody)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "create request")
+	}
+	// S3 does not accept chunked uploads
+	req.ContentLength = size
+	signRequest(req, payloadHash, c.config, time.Now())
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(ctx, err, "send request")
+	}
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+		if res.StatusCode == http.StatusNotFound && method == http.MethodGet && key != "" {
+			return nil, ErrNotFound
+		}
+		return nil, responseError(ctx, res)
+	}
+	return res, nil
+}
+
+// hashPayload returns the SHA256 hash and the size of the request body
+func hashPayload(body io.ReadSeeker) (string, int64, error) {
+	hash := sha256.New()
+	var size int64
+	if body != nil {
+		var err error
+		size, err = io.Copy(hash, body)
+		if err != nil {
+			return "", 0, err
+		}
+		_, err = body.Seek(0, io.SeekStart)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+func responseError(ctx context.Context, res *http.Response) error {
+	var s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	content, _ := io.ReadAll(io.LimitReader(res.Body, 64*1024))
+	err := xml.Unmarshal(content, &s3Error)
+	if err != nil || s3Error.Code == "" {
+		return errors.Newf(ctx, "unexpected response %s", res.Status)
+	}
+	return errors.Newf(ctx, "%s: %s (%s)", s3Error.Code, s3Error.Message, res.Status)
+}
@@ -0,0 +1,184 @@
+package top
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dustin/go-humanize"
+)
+
+type sortColumn struct {
+	Key     byte
+	Name    string
+	compare func(a, b containerSnapshot) int
+}
+
+// sortColumns are the columns the containers can be sorted by, with the key
+// selecting them
+var sortColumns = []sortColumn{
+	{Key: 'c', Name: "cpu", compare: func(a, b containerSnapshot) int { return cmp.Compare(a.cpu(), b.cpu()) }},
+	{Key: 'm', Name: "memory", compare: func(a, b containerSnapshot) int { return cmp.Compare(a.memoryRatio(), b.memoryRatio()) }},
+	{Key: 's', Name: "swap", compare: func(a, b containerSnapshot) int { return cmp.Compare(a.swapRatio(), b.swapRatio()) }},
+	{Key: 'n', Name: "name", compare: func(a, b containerSnapshot) int {
+		return cmp.Or(cmp.Compare(a.App, b.App), cmp.Compare(a.Name, b.Name))
+	}},
+}
+
+// state is what is displayed by the dashboard
+type state struct {
+	Snapshots   []appSnapshot
+	RefreshedAt time.Time
+	Refreshing  bool
+	Interval    time.Duration
+	Sort        sortColumn
+	// Descending is the order of the sort, the largest values first by default
+	Descending bool
+}
+
+// sortedContainers returns the containers of all the apps in the order of
+// the selected column. The name is used to keep a stable order between
+// refreshes.
+func (s *state) sortedContainers() []containerSnapshot {
+	var containers []containerSnapshot
+	for _, snapshot := range s.Snapshots {
+		containers = append(containers, snapshot.Containers...)
+	}
+	byName := sortColumns[len(sortColumns)-1].compare
+	slices.SortStableFunc(containers, func(a, b containerSnapshot) int {
+		res := s.Sort.compare(a, b)
+		if s.Descending {
+			res = -res
+		}
+		return cmp.Or(res, byName(a, b))
+	})
+	return containers
+}
+
+// render returns the lines of the dashboard fitting in a terminal of width
+// columns and height lines
+func render(s *state, width, height int, now time.Time) []string {
+	order := "asc"
+	if s.Descending {
+		order = "desc"
+	}
+	status := "updated " + s.RefreshedAt.Format("15:04:05")
+	if s.Refreshing {
+		status = "refreshing..."
+	}
+	if s.RefreshedAt.IsZero() {
+		status = "loading..."
+	}
+	lines := []string{
+		fmt.Sprintf("scalingo top - %s - refresh every %s - sorted by %s (%s)", status, s.Interval, s.Sort.Name, order),
+		"",
+	}
+
+	appsTable := [][]string{{"APP", "RPM", "5XX", "LAST DEPLOYMENT"}}
+	for _, snapshot := range s.Snapshots {
+		if snapshot.Err != nil {
+			appsTable = append(appsTable, []string{snapshot.App, "-", "-", "error: " + snapshot.Err.Error()})
+			continue
+		}
+		appsTable = append(appsTable, []string{snapshot.App, formatMetric(snapshot.RPM), formatMetric(snapshot.Router5XX), formatDeployment(snapshot, now)})
+	}
+	lines = append(lines, formatTable(appsTable)...)
+	lines = append(lines, "")
+
+	containersTable := [][]string{{"APP", "CONTAINER", "STATE", "CPU", "MEMORY", "SWAP"}}
+	for _, container := range s.sortedContainers() {
+		row := []string{container.App, container.Name, container.State, "-", "-", "-"}
+		if container.Stat != nil {
+			row[3] = fmt.Sprintf("%d%%", container.Stat.CPUUsage)
+			row[4] = formatUsage(container.memoryRatio(), container.Stat.MemoryUsage, container.Stat.MemoryLimit)
+			row[5] = formatUsage(container.swapRatio(), container.Stat.SwapUsage, container.Stat.SwapLimit)
+		}
+		containersTable = append(containersTable, row)
+	}
+	containerLines := formatTable(containersTable)
+
+	footer := "[c]pu [m]emory [s]wap [n]ame: sort  [r]everse  [space] refresh  [q]uit"
+	// The header, the apps, the containers header and the footer are always
+	// displayed, the containers fill the remaining lines
+	available := height - len(lines) - 1
+	if available < len(containerLines) {
+		if available > 1 {
+			hidden := len(containerLines) - available + 1
+			containerLines = append(containerLines[:available-1], fmt.Sprintf("... %d more containers", hidden))
+		} else {
+			containerLines = containerLines[:max(available, 0)]
+		}
+	}
+	lines = append(lines, containerLines...)
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, footer)
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
+
+// formatTable aligns the columns of the rows, the first row being the header
+func formatTable(rows [][]string) []string {
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var b strings.Builder
+		for i, cell := range row {
+			if i == len(row)-1 {
+				b.WriteString(cell)
+				break
+			}
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+2))
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string([]rune(s)[:max(width, 0)])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
+
+func formatUsage(ratio float64, usage, limit int64) string {
+	return fmt.Sprintf("%3.0f%% %s/%s", ratio*100, humanize.IBytes(uint64(usage)), humanize.IBytes(uint64(limit)))
+}
+
+func formatMetric(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f", *value)
+}
+
+func formatDeployment(snapshot appSnapshot, now time.Time) string {
+	deployment := snapshot.Deployment
+	if deployment == nil {
+		return "-"
+	}
+	if deployment.CreatedAt == nil {
+		return string(deployment.Status)
+	}
+	return fmt.Sprintf("%s (%s)", deployment.Status, humanize.RelTime(*deployment.CreatedAt, now, "ago", "from now"))
+}
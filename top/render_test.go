@@ -0,0 +1,62 @@
+package top
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Scalingo/go-scalingo/v11"
+)
+
+func TestRender(t *testing.T) {
+	now := time.Date(2026, 7, 18, 12, 0, 0, 0, time.UTC)
+	deployedAt := now.Add(-2 * time.Hour)
+	rpm := 120.0
+	s := &state{
+		RefreshedAt: now,
+		Interval:    5 * time.Second,
+		Sort:        sortColumns[0],
+		Descending:  true,
+		Snapshots: []appSnapshot{
+			{
+				App: "my-app",
+				Containers: []containerSnapshot{
+					{App: "my-app", Name: "web-1", State: "running", Stat: &scalingo.ContainerStat{CPUUsage: 10, MemoryUsage: 256 << 20, MemoryLimit: 512 << 20}},
+					{App: "my-app", Name: "web-2", State: "running", Stat: &scalingo.ContainerStat{CPUUsage: 80, MemoryUsage: 128 << 20, MemoryLimit: 512 << 20}},
+					{App: "my-app", Name: "worker-1", State: "starting"},
+				},
+				RPM:        &rpm,
+				Deployment: &scalingo.Deployment{Status: scalingo.StatusSuccess, CreatedAt: &deployedAt},
+			},
+			{App: "other-app", Err: errors.New("app not found")},
+		},
+	}
+
+	t.Run("it sorts the containers and fills the terminal", func(t *testing.T) {
+		lines := render(s, 100, 20, now)
+
+		require.Len(t, lines, 20)
+		assert.Contains(t, lines[3], "success (2 hours ago)")
+		assert.Contains(t, lines[3], "120")
+		assert.Contains(t, lines[4], "error: app not found")
+		assert.Contains(t, lines[7], "web-2")
+		assert.Contains(t, lines[8], "web-1")
+		assert.Contains(t, lines[8], " 50% 256 MiB/512 MiB")
+		assert.Contains(t, lines[9], "worker-1")
+		assert.True(t, strings.HasPrefix(lines[19], "[c]pu"))
+	})
+
+	t.Run("it truncates the containers and the lines to the terminal size", func(t *testing.T) {
+		lines := render(s, 40, 9, now)
+
+		require.Len(t, lines, 9)
+		assert.Equal(t, "... 3 more containers", lines[7])
+		for _, line := range lines {
+			assert.LessOrEqual(t, len([]rune(line)), 40)
+		}
+	})
+}
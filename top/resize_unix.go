@@ -0,0 +1,13 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package top
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyResize(signals chan os.Signal) {
+	signal.Notify(signals, syscall.SIGWINCH)
+}
@@ -0,0 +1,10 @@
+package top
+
+import (
+	"os"
+)
+
+// notifyResize does nothing on Windows, the size of the terminal is read at
+// each render
+func notifyResize(signals chan os.Signal) {
+}
@@ -0,0 +1,122 @@
+package top
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Scalingo/cli/metrics"
+	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// appSnapshot is the state of an application at a refresh
+type appSnapshot struct {
+	App        string
+	Containers []containerSnapshot
+	// RPM and Router5XX are the last values of the router metrics, nil if
+	// the app has no metric
+	RPM        *float64
+	Router5XX  *float64
+	Deployment *scalingo.Deployment
+	Err        error
+}
+
+type containerSnapshot struct {
+	App   string
+	Name  string
+	State string
+	Stat  *scalingo.ContainerStat
+}
+
+func (c containerSnapshot) memoryRatio() float64 {
+	if c.Stat == nil || c.Stat.MemoryLimit == 0 {
+		return 0
+	}
+	return float64(c.Stat.MemoryUsage) / float64(c.Stat.MemoryLimit)
+}
+
+func (c containerSnapshot) swapRatio() float64 {
+	if c.Stat == nil || c.Stat.SwapLimit == 0 {
+		return 0
+	}
+	return float64(c.Stat.SwapUsage) / float64(c.Stat.SwapLimit)
+}
+
+func (c containerSnapshot) cpu() int {
+	if c.Stat == nil {
+		return 0
+	}
+	return c.Stat.CPUUsage
+}
+
+// fetchSnapshots fetches the state of all the apps concurrently. The errors
+// are kept in the snapshot of each app so that the other apps are still
+// displayed.
+func fetchSnapshots(ctx context.Context, c *scalingo.Client, apps []string) []appSnapshot {
+	snapshots := make([]appSnapshot, len(apps))
+	wg := &sync.WaitGroup{}
+	for i, app := range apps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			snapshots[i] = fetchSnapshot(ctx, c, app)
+		}()
+	}
+	wg.Wait()
+	return snapshots
+}
+
+func fetchSnapshot(ctx context.Context, c *scalingo.Client, app string) appSnapshot {
+	snapshot := appSnapshot{App: app}
+
+	containers, err := c.AppsContainersPs(ctx, app)
+	if err != nil {
+		snapshot.Err = errors.Wrap(ctx, err, "list containers")
+		return snapshot
+	}
+	stats, err := c.AppsStats(ctx, app)
+	if err != nil {
+		snapshot.Err = errors.Wrap(ctx, err, "get containers stats")
+		return snapshot
+	}
+	statsByID := map[string]*scalingo.ContainerStat{}
+	for _, stat := range stats.Stats {
+		statsByID[stat.ID] = stat
+	}
+	for _, container := range containers {
+		name := container.Label
+		if name == "" {
+			name = fmt.Sprintf("%s-%d", container.Type, container.TypeIndex)
+		}
+		stat := statsByID[name]
+		if stat == nil {
+			stat = statsByID[container.ID]
+		}
+		snapshot.Containers = append(snapshot.Containers, containerSnapshot{
+			App:   app,
+			Name:  name,
+			State: container.State,
+			Stat:  stat,
+		})
+	}
+
+	// The router metrics and the deployment are optional: an app without web
+	// container has no router metric and a new app has no deployment
+	snapshot.RPM = lastMetricValue(ctx, c, app, scalingo.MetricRouterAll)
+	snapshot.Router5XX = lastMetricValue(ctx, c, app, scalingo.MetricRouter5XX)
+	deployments, err := c.DeploymentList(ctx, app)
+	if err == nil && len(deployments) > 0 {
+		snapshot.Deployment = deployments[0]
+	}
+	return snapshot
+}
+
+func lastMetricValue(ctx context.Context, c *scalingo.Client, app, metric string) *float64 {
+	points, err := metrics.Fetch(ctx, c, app, metrics.Query{Metric: metric, Since: time.Hour}, time.Now())
+	if err != nil || len(points) == 0 {
+		return nil
+	}
+	return &points[len(points)-1].Value
+}
@@ -0,0 +1,154 @@
+package top
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/term"
+
+	"github.com/Scalingo/cli/config"
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+const (
+	DefaultInterval = 5 * time.Second
+	MinInterval     = time.Second
+
+	enterAlternateScreen = "\033[?1049h\033[?25l"
+	leaveAlternateScreen = "\033[?25h\033[?1049l"
+	cursorHome           = "\033[H"
+	clearLine            = "\033[K"
+	clearScreenEnd       = "\033[J"
+)
+
+type Opts struct {
+	Interval time.Duration
+}
+
+// Run displays a full screen dashboard of the containers of the apps,
+// refreshed at the given interval, until the user quits. The terminal is
+// restored on exit.
+func Run(ctx context.Context, apps []string, opts Opts) error {
+	stdinFd, stdoutFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdinFd) || !term.IsTerminal(stdoutFd) {
+		return errors.New(ctx, "the dashboard requires a terminal")
+	}
+	if opts.Interval < MinInterval {
+		opts.Interval = DefaultInterval
+	}
+
+	c, err := config.ScalingoClient(ctx)
+	if err != nil {
+		return errors.Wrap(ctx, err, "get Scalingo client")
+	}
+
+	oldState, err := term.MakeRaw(stdinFd)
+	if err != nil {
+		return errors.Wrap(ctx, err, "set terminal in raw mode")
+	}
+	fmt.Print(enterAlternateScreen)
+	defer func() {
+		fmt.Print(leaveAlternateScreen)
+		_ = term.Restore(stdinFd, oldState)
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	keys := make(chan byte)
+	go readKeys(os.Stdin, keys)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	notifyResize(signals)
+	defer signal.Stop(signals)
+
+	s := &state{Interval: opts.Interval, Sort: sortColumns[0], Descending: true}
+	snapshots := make(chan []appSnapshot, 1)
+	refresh := func() {
+		if s.Refreshing {
+			return
+		}
+		s.Refreshing = true
+		go func() {
+			snapshots <- fetchSnapshots(ctx, c, apps)
+		}()
+	}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	refresh()
+	for {
+		draw(stdoutFd, s)
+		select {
+		case <-ctx.Done():
+			return nil
+		case sig := <-signals:
+			if sig == syscall.SIGTERM || sig == syscall.SIGHUP {
+				return nil
+			}
+			// The terminal has been resized, it is drawn again with its new size
+		case s.Snapshots = <-snapshots:
+			s.Refreshing = false
+			s.RefreshedAt = time.Now()
+		case <-ticker.C:
+			refresh()
+		case key, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			switch key {
+			case 'q', 'Q', 0x03, 0x1b:
+				// 0x03 is Ctrl-C and 0x1b Escape as the terminal is in raw mode
+				return nil
+			case 'r':
+				s.Descending = !s.Descending
+			case ' ':
+				refresh()
+			default:
+				for _, column := range sortColumns {
+					if column.Key == key {
+						// The names are sorted alphabetically, the other columns
+						// with the largest values first
+						s.Descending = column.Name != "name"
+						s.Sort = column
+					}
+				}
+			}
+		}
+	}
+}
+
+// draw renders the dashboard from the top of the screen, clearing the end
+// of the lines instead of the whole screen to avoid flickering
+func draw(fd int, s *state) {
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		width, height = 80, 24
+	}
+	lines := render(s, width, height, time.Now())
+	fmt.Print(cursorHome + strings.Join(lines, clearLine+"\r\n") + clearLine + clearScreenEnd)
+}
+
+// readKeys sends the bytes read from the terminal. The escape sequences are
+// ignored so that the arrow keys don't quit.
+func readKeys(f *os.File, keys chan<- byte) {
+	defer close(keys)
+	buffer := make([]byte, 16)
+	for {
+		n, err := f.Read(buffer)
+		if err != nil {
+			return
+		}
+		if n > 1 && buffer[0] == 0x1b {
+			continue
+		}
+		for _, b := range buffer[:n] {
+			keys <- b
+		}
+	}
+}
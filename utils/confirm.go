@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AlecAivazis/survey/v2"
+
+	"github.com/Scalingo/go-utils/errors/v3"
+)
+
+// Confirm asks the user a yes/no question. It returns false if the user
+// declines or if the answer can't be read.
+func Confirm(message string) bool {
+	result := false
+	prompt := &survey.Confirm{
+		Message: message,
+	}
+	_ = survey.AskOne(prompt, &result, nil)
+	return result
+}
+
+// ConfirmByTyping asks the user to type the expected value (usually the name
+// of the application impacted) to confirm a dangerous operation. The prompt
+// is displayed on stderr. It returns an error if the typed value differs.
+func ConfirmByTyping(ctx context.Context, prompt, expected string) error {
+	fmt.Fprintf(os.Stderr, "%s\nTo confirm type '%s': ", prompt, expected)
+	validation, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return errors.Wrap(ctx, err, "read confirmation from stdin")
+	}
+	validation = strings.TrimSpace(validation)
+
+	if validation != expected {
+		return errors.Newf(ctx, "'%s' is not '%s', aborting…", validation, expected)
+	}
+	return nil
+}
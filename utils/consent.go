@@ -6,6 +6,8 @@ import (
 	"os"
 	"time"
 
+	"golang.org/x/term"
+
 	"github.com/Scalingo/cli/config"
 	"github.com/Scalingo/cli/io"
 	"github.com/Scalingo/go-utils/errors/v3"
@@ -21,6 +23,33 @@ const (
 // CheckForConsent will check if an operator does have consent before executing a command. If they doesn't or if the CLI can't determine if the user has consent, it will ask for the operator confirmation.
 // All display takes place on Stderr to minimize the chance that it will collide in situation where stdout is piped to another process (typically `scalingo logs | grep SOMETHING`).
 func CheckForConsent(ctx context.Context, resourceName string, consentTypes ...ConsentType) {
+	ask, override := consentNeeded(ctx, resourceName, consentTypes...)
+	if ask {
+		askForConsent(override)
+	}
+}
+
+// EnsureConsent checks the consent like CheckForConsent, but it returns an
+// error instead of asking for the operator confirmation when stdin is not a
+// terminal, e.g. when the command is run by a cron job.
+func EnsureConsent(ctx context.Context, resourceName string, consentTypes ...ConsentType) error {
+	ask, override := consentNeeded(ctx, resourceName, consentTypes...)
+	if !ask {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		if override {
+			return errors.Newf(ctx, "no consent to access %s", resourceName)
+		}
+		return errors.Newf(ctx, "the consent to access %s can't be checked and can't be confirmed without a terminal", resourceName)
+	}
+	askForConsent(override)
+	return nil
+}
+
+// consentNeeded returns whether the operator must be asked for consent, and
+// whether it is an override of a missing consent.
+func consentNeeded(ctx context.Context, resourceName string, consentTypes ...ConsentType) (bool, bool) {
 	needConsentForContainers := false
 	needConsentForDBs := false
 
@@ -40,53 +69,49 @@ func CheckForConsent(ctx context.Context, resourceName string, consentTypes ...C
 
 	currentUser, err := config.C.CurrentUser(ctx)
 	if err != nil {
-		return
+		return false, false
 	}
 
 	// If the user is not admin, exit immediately, this will make this function
 	// almost a NOOP for non operators.
 	if !currentUser.Flags["admin"] {
-		return
+		return false, false
 	}
 
 	// From this point out, if we encounter an error, we try to safely recover by manually asking the operator to override.
 	c, err := config.ScalingoClient(ctx)
 	if err != nil {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	// Check if the operator is a collaborator on the targeted app
 	apps, err := c.AppsList(ctx)
 	if err != nil {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	for _, app := range apps {
 		if app.Name == resourceName {
 			// The operator is a collaborator, no consent needed
-			return
+			return false, false
 		}
 	}
 
 	dbClient, err := config.ScalingoDatabaseClient(ctx)
 	if err != nil {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	// Check if the operator is a collaborator on the targeted database
 	dbs, err := dbClient.DatabasesList(ctx)
 	if err != nil {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	for _, db := range dbs {
 		if db.Name == resourceName || db.ID == resourceName {
 			// The operator is a collaborator, no consent needed
-			return
+			return false, false
 		}
 	}
 
@@ -94,34 +119,31 @@ func CheckForConsent(ctx context.Context, resourceName string, consentTypes ...C
 
 	app, err := c.AppsShow(ctx, resourceName)
 	if err != nil {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	if app.DataAccessConsent == nil {
 		// No consent for this app, asking for an override
-		askForConsent(true)
-		return
+		return true, true
 	}
 
 	isDB, err := IsResourceDatabase(ctx, resourceName)
 	if err != nil && !errors.Is(err, ErrResourceNotFound) {
-		askForConsent(false)
-		return
+		return true, false
 	}
 
 	containers := checkAccessContent(app.DataAccessConsent.ContainersUntil)
 	databases := checkAccessContent(app.DataAccessConsent.DatabasesUntil)
 
 	if needConsentForContainers && !containers && !isDB {
-		askForConsent(true)
-		return
+		return true, true
 	}
 
 	if needConsentForDBs && !databases {
-		askForConsent(true)
-		return
+		return true, true
 	}
+
+	return false, false
 }
 
 func askForConsent(override bool) {
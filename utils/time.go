@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Scalingo/go-scalingo/v11"
+	"github.com/Scalingo/go-utils/errors/v3"
 )
 
 const (
@@ -27,6 +30,16 @@ func FormatMaintenanceWindowWithTimezone(maintenanceWindow scalingo.MaintenanceW
 	)
 }
 
+// ParseWeekday returns the weekday from its English name, case insensitive
+func ParseWeekday(ctx context.Context, name string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, nil
+		}
+	}
+	return time.Sunday, errors.Newf(ctx, "invalid weekday '%s'", name)
+}
+
 func ConvertDayAndHourToTimezone(weekday time.Weekday, hour int, inputLocation *time.Location, outputLocation *time.Location) (time.Weekday, int) {
 	newTimezoneDate := beginningOfWeek(time.Now().In(inputLocation))
 